@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Coverage bar dimensions and thresholds
+const (
+	coverageBarWidth  = 10
+	coverageGoodLevel = 90.0
+	coverageFairLevel = 60.0
+	ansiReset         = "\033[0m"
+	ansiRed           = "\033[31m"
+	ansiGreen         = "\033[32m"
+	ansiYellow        = "\033[33m"
+)
+
+// RenderCoverageBar renders a coverage percentage as a fixed-width bar,
+// e.g. "[████████░░] 80%"
+func RenderCoverageBar(percent float64, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	percent = math.Max(0, math.Min(100, percent))
+	filled := int(math.Round(percent / 100 * coverageBarWidth))
+
+	fillChar, emptyChar := "█", "░"
+	if !opts.Emoji {
+		fillChar, emptyChar = "#", "-"
+	}
+
+	bar := strings.Repeat(fillChar, filled) + strings.Repeat(emptyChar, coverageBarWidth-filled)
+
+	// Only the bar itself is colored so the visible width stays the same
+	if opts.Colorize {
+		bar = coverageColor(percent) + bar + ansiReset
+	}
+
+	return fmt.Sprintf("[%s] %d%%", bar, int(math.Round(percent)))
+}
+
+// coverageColor picks the ANSI color for a coverage level
+func coverageColor(percent float64) string {
+	switch {
+	case percent >= coverageGoodLevel:
+		return ansiGreen
+	case percent >= coverageFairLevel:
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
@@ -0,0 +1,24 @@
+package checker
+
+import "strings"
+
+// booleanForms maps the accepted spellings of booleans to their value
+var booleanForms = map[string]bool{
+	"true": true, "1": true, "yes": true, "on": true, "y": true, "enabled": true,
+	"false": false, "0": false, "no": false, "off": false, "n": false, "disabled": false,
+}
+
+// normalizeBool canonicalizes a boolean-ish value such as "Yes" or "off".
+// The second result is false when value is not a recognized form.
+func normalizeBool(value string) (bool, bool) {
+	b, ok := booleanForms[strings.ToLower(strings.TrimSpace(value))]
+	return b, ok
+}
+
+// sameBool reports whether two values are recognized booleans with the same
+// meaning, e.g. "true" and "1"
+func sameBool(a, b string) bool {
+	x, okA := normalizeBool(a)
+	y, okB := normalizeBool(b)
+	return okA && okB && x == y
+}
@@ -0,0 +1,200 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// BranchFileDiff is how the variables of one file changed between two git refs
+type BranchFileDiff struct {
+	Path        string   `json:"path"`
+	Added       []string `json:"added"`   // Only at the head ref
+	Removed     []string `json:"removed"` // Only at the base ref
+	MissingBase bool     `json:"missing_base,omitempty"`
+	MissingHead bool     `json:"missing_head,omitempty"`
+}
+
+// HasChanges reports whether the file's variables differ between the refs
+func (d *BranchFileDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// BranchDiff is the configuration difference between two git refs
+type BranchDiff struct {
+	Base    string          `json:"base"`
+	Head    string          `json:"head"`
+	Example BranchFileDiff  `json:"example"`
+	Compose *BranchFileDiff `json:"compose,omitempty"`
+}
+
+// HasChanges reports whether any compared file differs between the refs
+func (d *BranchDiff) HasChanges() bool {
+	return d.Example.HasChanges() || (d.Compose != nil && d.Compose.HasChanges())
+}
+
+// CompareBranches compares the variables of exampleFile, and of composeFile
+// unless it is empty, as they are at the base and head git refs. Paths are
+// relative to the repository root. A file missing at one ref counts as
+// having no variables there; a missing or invalid ref is an error.
+func CompareBranches(base, head, exampleFile, composeFile string, opts *parser.ParseOptions) (*BranchDiff, error) {
+	for _, ref := range []string{base, head} {
+		if err := parser.VerifyGitRef(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := &BranchDiff{Base: base, Head: head}
+
+	exampleKeys := func(ref string) ([]string, error) {
+		exampleParse := parser.DefaultParseOptions()
+		if opts != nil {
+			*exampleParse = *opts
+		}
+		exampleParse.Docker = false
+		exampleParse.CommentedKeys = true
+
+		parsed, err := parser.ParseEnvFileWithOptions(parser.GitSource(ref, exampleFile), exampleParse)
+		if err != nil {
+			return nil, err
+		}
+		return parsed.Keys(), nil
+	}
+
+	example, err := compareAtRefs(base, head, exampleFile, exampleKeys)
+	if err != nil {
+		return nil, err
+	}
+	diff.Example = *example
+
+	if composeFile != "" {
+		composeRefs := func(ref string) ([]string, error) {
+			data, err := parser.ReadGitSource(parser.GitSource(ref, composeFile))
+			if err != nil {
+				return nil, err
+			}
+			info, err := parser.ParseComposeData(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s at %s: %w", composeFile, ref, err)
+			}
+			return info.VariableRefs, nil
+		}
+
+		diff.Compose, err = compareAtRefs(base, head, composeFile, composeRefs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diff, nil
+}
+
+// compareAtRefs diffs the keys that read returns for path at the two refs
+func compareAtRefs(base, head, path string, read func(ref string) ([]string, error)) (*BranchFileDiff, error) {
+	diff := &BranchFileDiff{Path: path, Added: []string{}, Removed: []string{}}
+
+	baseKeys, err := read(base)
+	if errors.Is(err, parser.ErrNotInGitRef) {
+		diff.MissingBase = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	headKeys, err := read(head)
+	if errors.Is(err, parser.ErrNotInGitRef) {
+		diff.MissingHead = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	if diff.MissingBase && diff.MissingHead {
+		return nil, fmt.Errorf("%s exists at neither %s nor %s", path, base, head)
+	}
+
+	result := CompareEnvVars(keySet(headKeys), keySet(baseKeys))
+	diff.Added = append(diff.Added, result.Extra...)
+	diff.Removed = append(diff.Removed, result.Missing...)
+
+	return diff, nil
+}
+
+// keySet turns a key list into EnvVars with empty values
+func keySet(keys []string) parser.EnvVars {
+	vars := make(parser.EnvVars, len(keys))
+	for _, key := range keys {
+		vars[key] = ""
+	}
+	return vars
+}
+
+// GenerateBranchDiffReport creates a formatted report of the configuration
+// a head ref adds and removes relative to a base ref
+func GenerateBranchDiffReport(diff *BranchDiff, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !diff.HasChanges() {
+		report.WriteString(fmt.Sprintf("No configuration changes from %s to %s.\n", diff.Base, diff.Head))
+		return report.String()
+	}
+
+	if opts.Colorize {
+		report.WriteString(emoji(opts, "🔀") + fmt.Sprintf("Configuration changes from %s to %s:\n\n", diff.Base, diff.Head))
+	} else {
+		report.WriteString(fmt.Sprintf("Configuration changes from %s to %s:\n\n", diff.Base, diff.Head))
+	}
+
+	files := []*BranchFileDiff{&diff.Example}
+	if diff.Compose != nil {
+		files = append(files, diff.Compose)
+	}
+
+	for _, file := range files {
+		if !file.HasChanges() {
+			continue
+		}
+
+		report.WriteString(file.Path + ":\n")
+		switch {
+		case file.MissingBase:
+			report.WriteString(fmt.Sprintf("  (new in %s)\n", diff.Head))
+		case file.MissingHead:
+			report.WriteString(fmt.Sprintf("  (deleted in %s)\n", diff.Head))
+		}
+
+		if len(file.Added) > 0 {
+			if opts.Colorize {
+				report.WriteString("  " + emoji(opts, "➕") + fmt.Sprintf("Added in %s:\n", diff.Head))
+			} else {
+				report.WriteString(fmt.Sprintf("  Added in %s:\n", diff.Head))
+			}
+			writeKeyList(&report, file.Added, "    ", opts)
+		}
+		if len(file.Removed) > 0 {
+			if opts.Colorize {
+				report.WriteString("  " + emoji(opts, "➖") + fmt.Sprintf("Removed in %s:\n", diff.Head))
+			} else {
+				report.WriteString(fmt.Sprintf("  Removed in %s:\n", diff.Head))
+			}
+			writeKeyList(&report, file.Removed, "    ", opts)
+		}
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
+
+// GenerateBranchDiffJSON renders the branch diff as indented JSON
+func GenerateBranchDiffJSON(diff *BranchDiff) (string, error) {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode branch diff: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// LocatedFinding is a finding with the file and line it points at
+type LocatedFinding struct {
+	Finding
+	File string
+	Line int // 1-based, 0 when the key has no line in File
+}
+
+// LocateFindings points env findings at a line: every finding but a missing
+// key at the file and line its key was last set on (see
+// DiffResult.Locations), others at the key's declaration in exampleFile.
+// Without locations (the env side is not a file, e.g. a container), all
+// findings point at exampleFile.
+func LocateFindings(findings []Finding, locations map[string]KeyLocation, exampleFile string, opts *parser.ParseOptions) ([]LocatedFinding, error) {
+	exampleParse := parser.DefaultParseOptions()
+	if opts != nil {
+		*exampleParse = *opts
+	}
+	exampleParse.Docker = false
+
+	example, err := loader.ParseEnvFileWithOptions(exampleFile, exampleParse)
+	if err != nil {
+		return nil, err
+	}
+
+	located := make([]LocatedFinding, 0, len(findings))
+	for _, f := range findings {
+		if location, ok := locations[f.Key]; ok && f.Category != "missing" {
+			located = append(located, LocatedFinding{Finding: f, File: location.File, Line: location.Line})
+			continue
+		}
+
+		line := 0
+		if entry, ok := example.Lookup(f.Key); ok {
+			line = entry.Line
+		}
+		located = append(located, LocatedFinding{Finding: f, File: example.Filename, Line: line})
+	}
+
+	return located, nil
+}
+
+// checkstyleReport is the root element of a Checkstyle XML report
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+// checkstyleFile holds the errors of one file
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+// checkstyleError is one finding
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity maps a severity onto Checkstyle's error, warning and info
+func checkstyleSeverity(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+// GenerateCheckstyleReport renders located findings as Checkstyle XML, one
+// <file> per file in name order with its findings in line order. A clean
+// run produces an empty <checkstyle> element.
+func GenerateCheckstyleReport(findings []LocatedFinding) (string, error) {
+	byFile := make(map[string][]LocatedFinding)
+	for _, f := range findings {
+		byFile[f.File] = append(byFile[f.File], f)
+	}
+
+	names := make([]string, 0, len(byFile))
+	for name := range byFile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := checkstyleReport{Version: "4.3", Files: []checkstyleFile{}}
+	for _, name := range names {
+		fileFindings := byFile[name]
+		sort.SliceStable(fileFindings, func(i, j int) bool {
+			return fileFindings[i].Line < fileFindings[j].Line
+		})
+
+		file := checkstyleFile{Name: name}
+		for _, f := range fileFindings {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     f.Line,
+				Severity: checkstyleSeverity(f.Severity),
+				Message:  f.Message,
+				Source:   "envquack." + f.Category,
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Checkstyle report: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
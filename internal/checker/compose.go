@@ -2,6 +2,8 @@ package checker
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,31 +13,90 @@ import (
 
 // ComposeDiffResult represents comparison between env files and compose file
 type ComposeDiffResult struct {
-	MissingInEnv     []string            // Variables in compose but not in env files
-	ExtraInEnv       []string            // Variables in env files but not used in compose
-	MissingEnvFiles  []string            // env_file references that don't exist
-	ServiceBreakdown map[string][]string // Missing variables by service
+	MissingInEnv         []string            // Variables in compose but not in env files
+	ExtraInEnv           []string            // Variables in env files but not used in compose
+	MissingEnvFiles      []string            // env_file references that don't exist
+	UnignoredSecretFiles []string            // env_files with secret-looking values not covered by .gitignore
+	SecretFilesInBuild   []string            // env_files with secret-looking values not excluded by .dockerignore
+	ServiceBreakdown     map[string][]string // Missing variables by service
+	PortRefs             map[string][]string // Variables each service references in its ports mappings
+	UnknownServices      []string            // --only-services patterns that match no service
+	UnconfiguredServices []string            // Services with neither environment nor env_file (informational)
+	SharedEnvFiles       []SharedEnvFile     // env_files referenced by several services (informational)
+
+	Locations map[string][]parser.ComposeLocation // Where each missing variable appears in the compose file
+}
+
+// locationSummary describes where key appears in the compose file, or "" if unknown
+func (c *ComposeDiffResult) locationSummary(key string) string {
+	locs := c.Locations[key]
+	if len(locs) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(locs))
+	for _, loc := range locs {
+		parts = append(parts, loc.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// usedInPorts reports whether service references key in its ports mappings
+func (c *ComposeDiffResult) usedInPorts(service, key string) bool {
+	for _, ref := range c.PortRefs[service] {
+		if ref == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ComposeOptions configures a compose comparison
+type ComposeOptions struct {
+	OnlyServices []string // Restrict the comparison to services matching these patterns (see parser.ComposeEnvInfo.MatchServices), empty means all
+}
+
+// DefaultComposeOptions returns default compose comparison options
+func DefaultComposeOptions() *ComposeOptions {
+	return &ComposeOptions{}
 }
 
 // HasIssues returns true if there are any issues
 func (c *ComposeDiffResult) HasIssues() bool {
 	return len(c.MissingInEnv) > 0 ||
 		len(c.ExtraInEnv) > 0 ||
-		len(c.MissingEnvFiles) > 0
+		len(c.MissingEnvFiles) > 0 ||
+		len(c.UnignoredSecretFiles) > 0 ||
+		len(c.SecretFilesInBuild) > 0
 }
 
 // CompareComposeWithEnv compares docker-compose requirements against env files
-func CompareComposeWithEnv(composeFile string, envFiles []string) (*ComposeDiffResult, error) {
+func CompareComposeWithEnv(composeFile string, envFiles []string, opts *ComposeOptions) (*ComposeDiffResult, error) {
+	if opts == nil {
+		opts = DefaultComposeOptions()
+	}
+
 	// Parse compose file
-	composeInfo, err := parser.ParseComposeFile(composeFile)
+	composeInfo, err := loader.ParseComposeFile(composeFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse compose file: %w", err)
 	}
 
+	// Narrow to the selected services
+	var unknown []string
+	if len(opts.OnlyServices) > 0 {
+		selected, unmatched, err := composeInfo.MatchServices(opts.OnlyServices)
+		if err != nil {
+			return nil, err
+		}
+		composeInfo, _ = composeInfo.FilterServices(selected)
+		unknown = unmatched
+	}
+
 	// Parse all env files
 	allEnvVars := make(parser.EnvVars)
 	for _, envFile := range envFiles {
-		envVars, err := parser.ParseEnvFile(envFile)
+		envVars, err := loader.ParseEnvFile(envFile)
 		if err != nil {
 			// Skip missing files, we'll report them separately
 			continue
@@ -47,16 +108,26 @@ func CompareComposeWithEnv(composeFile string, envFiles []string) (*ComposeDiffR
 		}
 	}
 
-	return compareComposeWithEnvVars(composeInfo, allEnvVars), nil
+	result := compareComposeWithEnvVars(composeInfo, allEnvVars)
+	result.UnknownServices = append(result.UnknownServices, unknown...)
+
+	return result, nil
 }
 
 // compareComposeWithEnvVars performs the actual comparison logic
 func compareComposeWithEnvVars(composeInfo *parser.ComposeEnvInfo, envVars parser.EnvVars) *ComposeDiffResult {
 	result := &ComposeDiffResult{
-		MissingInEnv:     []string{},
-		ExtraInEnv:       []string{},
-		MissingEnvFiles:  []string{},
-		ServiceBreakdown: make(map[string][]string),
+		MissingInEnv:         []string{},
+		ExtraInEnv:           []string{},
+		MissingEnvFiles:      []string{},
+		UnignoredSecretFiles: []string{},
+		SecretFilesInBuild:   []string{},
+		ServiceBreakdown:     make(map[string][]string),
+		PortRefs:             composeInfo.PortRefs,
+		UnknownServices:      []string{},
+		UnconfiguredServices: composeInfo.ServicesWithoutEnv(),
+		SharedEnvFiles:       findSharedEnvFiles(composeInfo),
+		Locations:            make(map[string][]parser.ComposeLocation),
 	}
 
 	// Get all variables referenced in compose
@@ -70,6 +141,9 @@ func compareComposeWithEnvVars(composeInfo *parser.ComposeEnvInfo, envVars parse
 	for _, composeVar := range composeVars {
 		if !envVars.Has(composeVar) {
 			result.MissingInEnv = append(result.MissingInEnv, composeVar)
+			if locs := composeInfo.Locations[composeVar]; len(locs) > 0 {
+				result.Locations[composeVar] = locs
+			}
 		}
 	}
 
@@ -80,15 +154,26 @@ func compareComposeWithEnvVars(composeInfo *parser.ComposeEnvInfo, envVars parse
 		}
 	}
 
-	// Check service-specific breakdowns
-	for serviceName, serviceVars := range composeInfo.ServiceVars {
-		missing := []string{}
-		for varName := range serviceVars {
+	// Check service-specific breakdowns, covering both defined variables and
+	// references anywhere in the service
+	for _, serviceName := range composeInfo.ServiceNames {
+		missingSet := make(map[string]bool)
+		for varName := range composeInfo.ServiceVars[serviceName] {
 			if !envVars.Has(varName) {
-				missing = append(missing, varName)
+				missingSet[varName] = true
 			}
 		}
-		if len(missing) > 0 {
+		for _, ref := range composeInfo.ServiceRefs[serviceName] {
+			if !envVars.Has(ref) {
+				missingSet[ref] = true
+			}
+		}
+
+		if len(missingSet) > 0 {
+			missing := make([]string, 0, len(missingSet))
+			for varName := range missingSet {
+				missing = append(missing, varName)
+			}
 			sort.Strings(missing)
 			result.ServiceBreakdown[serviceName] = missing
 		}
@@ -96,7 +181,7 @@ func compareComposeWithEnvVars(composeInfo *parser.ComposeEnvInfo, envVars parse
 
 	// Check for missing env files referenced in compose
 	for _, envFile := range composeInfo.EnvFiles {
-		if _, err := parser.ParseEnvFile(envFile); err != nil {
+		if _, err := loader.ParseEnvFile(envFile); err != nil {
 			result.MissingEnvFiles = append(result.MissingEnvFiles, envFile)
 		}
 	}
@@ -109,6 +194,159 @@ func compareComposeWithEnvVars(composeInfo *parser.ComposeEnvInfo, envVars parse
 	return result
 }
 
+// ServiceGap lists the variables a single service is missing
+type ServiceGap struct {
+	Service string
+	Missing []string
+}
+
+// IncompleteServices returns the services missing required variables, worst
+// offenders (most missing variables) first
+func (c *ComposeDiffResult) IncompleteServices() []ServiceGap {
+	gaps := make([]ServiceGap, 0, len(c.ServiceBreakdown))
+	for service, missing := range c.ServiceBreakdown {
+		gaps = append(gaps, ServiceGap{Service: service, Missing: missing})
+	}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		if len(gaps[i].Missing) != len(gaps[j].Missing) {
+			return len(gaps[i].Missing) > len(gaps[j].Missing)
+		}
+		return gaps[i].Service < gaps[j].Service
+	})
+
+	return gaps
+}
+
+// GenerateServiceGateReport explains which services fail the
+// every-service-must-be-satisfied gate
+func GenerateServiceGateReport(gaps []ServiceGap, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if len(gaps) == 0 {
+		if opts.Plain {
+			report.WriteString("Service gate passed: every service has its required variables.\n")
+		} else {
+			report.WriteString(emoji(opts, "✅") + "Every service has its required variables.\n")
+		}
+		return report.String()
+	}
+
+	if opts.Colorize {
+		report.WriteString(emoji(opts, "⛔") + fmt.Sprintf("%d services are missing required variables (worst first):\n", len(gaps)))
+	} else {
+		report.WriteString(fmt.Sprintf("Incomplete services (%d, worst first):\n", len(gaps)))
+	}
+
+	lines := make([]string, 0, len(gaps))
+	for _, gap := range gaps {
+		lines = append(lines, fmt.Sprintf("%s: %d missing (%s)", gap.Service, len(gap.Missing), strings.Join(gap.Missing, ", ")))
+	}
+	writeKeyList(&report, lines, "  ", opts)
+
+	return report.String()
+}
+
+// GenerateUnconfiguredServicesReport lists services without any environment
+// configuration, so the omission can be confirmed as intentional
+func GenerateUnconfiguredServicesReport(services []string, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	if len(services) == 0 {
+		return ""
+	}
+
+	var report strings.Builder
+	writeHeading(&report, opts, "ℹ️ ", "Services with no environment or env_file (intentional?)", "Services without environment or env_file")
+	writeKeyList(&report, services, "  ", opts)
+
+	return report.String()
+}
+
+// CheckGitignoreCoverage records env_files referenced by the compose file that
+// contain secret-looking values but are not covered by gitignoreFile. A
+// missing .gitignore covers nothing.
+func CheckGitignoreCoverage(result *ComposeDiffResult, composeFile, gitignoreFile string) error {
+	composeInfo, err := loader.ParseComposeFile(composeFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	matcher, err := parser.ParseGitignore(gitignoreFile)
+	if os.IsNotExist(err) {
+		matcher = &parser.IgnoreMatcher{}
+	} else if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", gitignoreFile, err)
+	}
+
+	for _, envFile := range composeInfo.EnvFiles {
+		vars, err := loader.ParseEnvFile(envFile)
+		if err != nil {
+			// Missing env files are reported separately
+			continue
+		}
+
+		// Patterns are relative to the directory holding the .gitignore
+		relPath, err := filepath.Rel(filepath.Dir(gitignoreFile), envFile)
+		if err != nil {
+			relPath = envFile
+		}
+
+		if hasSecretValues(vars) && !matcher.Matches(relPath) {
+			result.UnignoredSecretFiles = append(result.UnignoredSecretFiles, envFile)
+		}
+	}
+
+	sort.Strings(result.UnignoredSecretFiles)
+	return nil
+}
+
+// CheckDockerignoreCoverage records env_files referenced by the compose file
+// that contain secret-looking values and sit inside the build context (the
+// directory holding dockerignoreFile) without being excluded by it, so a
+// `COPY . .` would bake them into the image. A missing .dockerignore
+// excludes nothing.
+func CheckDockerignoreCoverage(result *ComposeDiffResult, composeFile, dockerignoreFile string) error {
+	composeInfo, err := loader.ParseComposeFile(composeFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	matcher, err := parser.ParseDockerignore(dockerignoreFile)
+	if os.IsNotExist(err) {
+		matcher = &parser.IgnoreMatcher{}
+	} else if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", dockerignoreFile, err)
+	}
+
+	for _, envFile := range composeInfo.EnvFiles {
+		vars, err := loader.ParseEnvFile(envFile)
+		if err != nil {
+			// Missing env files are reported separately
+			continue
+		}
+
+		// Files outside the build context are never sent to the builder
+		relPath, err := filepath.Rel(filepath.Dir(dockerignoreFile), envFile)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if hasSecretValues(vars) && !matcher.Matches(relPath) {
+			result.SecretFilesInBuild = append(result.SecretFilesInBuild, envFile)
+		}
+	}
+
+	sort.Strings(result.SecretFilesInBuild)
+	return nil
+}
+
 // GenerateComposeReport creates a formatted report for compose comparison
 func GenerateComposeReport(result *ComposeDiffResult, opts *ReportOptions) string {
 	if opts == nil {
@@ -118,7 +356,11 @@ func GenerateComposeReport(result *ComposeDiffResult, opts *ReportOptions) strin
 	var report strings.Builder
 
 	if !result.HasIssues() {
-		report.WriteString("✅ Docker Compose environment is aligned.\n")
+		if opts.Plain {
+			report.WriteString("Docker Compose check passed: environment is aligned.\n")
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + "Docker Compose environment is aligned.\n")
 		if opts.ShowDuck {
 			report.WriteString("(Your gopher-duck approves of your container setup!)\n")
 		}
@@ -126,67 +368,87 @@ func GenerateComposeReport(result *ComposeDiffResult, opts *ReportOptions) strin
 	}
 
 	// Header with duck
-	if opts.ShowDuck {
-		report.WriteString(quack.GetAngryDuck() + "\n")
-		report.WriteString("QUACK! 🦆 Docker Compose environment issues detected:\n\n")
+	if opts.Plain {
+		report.WriteString(fmt.Sprintf("Docker Compose check failed: %d missing, %d unused, %d missing env_files, %d secret env_files not gitignored, %d not dockerignored\n\n",
+			len(result.MissingInEnv), len(result.ExtraInEnv), len(result.MissingEnvFiles), len(result.UnignoredSecretFiles), len(result.SecretFilesInBuild)))
+	} else if opts.ShowDuck {
+		missing := len(result.MissingInEnv) + len(result.MissingEnvFiles) + len(result.UnignoredSecretFiles) + len(result.SecretFilesInBuild)
+		report.WriteString(quack.GetDuckForSeverity(missing, len(result.ExtraInEnv)) + "\n")
+		report.WriteString("QUACK! " + emoji(opts, "🦆") + "Docker Compose environment issues detected:\n\n")
 	}
 
 	// Missing env files
 	if len(result.MissingEnvFiles) > 0 {
-		if opts.Colorize {
-			report.WriteString("💥 Missing env_files referenced in compose:\n")
-		} else {
-			report.WriteString("Missing env_files:\n")
-		}
+		writeHeading(&report, opts, "💥", "Missing env_files referenced in compose", "Missing env_files")
 
-		for _, file := range result.MissingEnvFiles {
-			report.WriteString(fmt.Sprintf("  - %s\n", file))
-		}
+		writeKeyList(&report, result.MissingEnvFiles, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Secret env files that could be committed
+	if len(result.UnignoredSecretFiles) > 0 {
+		writeHeading(&report, opts, "🔐", "env_files with secrets that are not gitignored", "Secret env_files not covered by .gitignore")
+
+		writeKeyList(&report, result.UnignoredSecretFiles, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Secret env files that would be copied into images
+	if len(result.SecretFilesInBuild) > 0 {
+		writeHeading(&report, opts, "📦", "env_files with secrets that are not in .dockerignore", "Secret env_files not excluded by .dockerignore")
+
+		writeKeyList(&report, result.SecretFilesInBuild, "  ", opts)
 		report.WriteString("\n")
 	}
 
 	// Missing variables
 	if len(result.MissingInEnv) > 0 {
-		if opts.Colorize {
-			report.WriteString("🔴 Variables required by compose but missing in env files:\n")
-		} else {
-			report.WriteString("Missing variables:\n")
-		}
+		writeHeading(&report, opts, "🔴", "Variables required by compose but missing in env files", "Missing variables")
 
+		lines := make([]string, 0, len(result.MissingInEnv))
 		for _, key := range result.MissingInEnv {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
+			if where := result.locationSummary(key); where != "" {
+				key += " (" + where + ")"
+			}
+			lines = append(lines, key)
 		}
+		writeKeyList(&report, lines, "  ", opts)
 		report.WriteString("\n")
 	}
 
 	// Service breakdown
 	if len(result.ServiceBreakdown) > 0 && opts.Verbose {
-		report.WriteString("📋 Service breakdown:\n")
-		for serviceName, missing := range result.ServiceBreakdown {
+		writeHeading(&report, opts, "📋", "Service breakdown", "Service breakdown")
+		services := make([]string, 0, len(result.ServiceBreakdown))
+		for serviceName := range result.ServiceBreakdown {
+			services = append(services, serviceName)
+		}
+		sort.Strings(services)
+
+		for _, serviceName := range services {
 			report.WriteString(fmt.Sprintf("  %s:\n", serviceName))
-			for _, varName := range missing {
-				report.WriteString(fmt.Sprintf("    - %s\n", varName))
+			lines := make([]string, 0, len(result.ServiceBreakdown[serviceName]))
+			for _, key := range result.ServiceBreakdown[serviceName] {
+				if result.usedInPorts(serviceName, key) {
+					key += " (required for port mapping)"
+				}
+				lines = append(lines, key)
 			}
+			writeKeyList(&report, lines, "    ", opts)
 		}
 		report.WriteString("\n")
 	}
 
 	// Extra variables (usually less critical)
 	if len(result.ExtraInEnv) > 0 {
-		if opts.Colorize {
-			report.WriteString("🟡 Variables in env files but not used in compose:\n")
-		} else {
-			report.WriteString("Unused variables:\n")
-		}
+		writeHeading(&report, opts, "🟡", "Variables in env files but not used in compose", "Unused variables")
 
-		for _, key := range result.ExtraInEnv {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
-		}
+		writeKeyList(&report, result.ExtraInEnv, "  ", opts)
 		report.WriteString("\n")
 	}
 
 	// Footer with duck message
-	if opts.ShowDuck {
+	if opts.ShowDuck && !opts.Plain {
 		report.WriteString("(Your gopher-duck is confused by your container setup!)\n")
 	}
 
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsets are the character sets @charset accepts, each checking a whole value
+var charsets = map[string]func(value string) bool{
+	"hex":          isHex,
+	"base64":       isBase64,
+	"alphanumeric": isAlphanumeric,
+	"ascii":        isASCII,
+}
+
+// validateLength checks that value is exactly as long as @length says
+func validateLength(value, arg string) error {
+	want, err := lengthArg("length", arg)
+	if err != nil {
+		return err
+	}
+	if got := utf8.RuneCountInString(value); got != want {
+		return fmt.Errorf("length %d, expected %d", got, want)
+	}
+	return nil
+}
+
+// validateMinLength checks that value is at least as long as @minlength says
+func validateMinLength(value, arg string) error {
+	want, err := lengthArg("minlength", arg)
+	if err != nil {
+		return err
+	}
+	if got := utf8.RuneCountInString(value); got < want {
+		return fmt.Errorf("length %d, expected at least %d", got, want)
+	}
+	return nil
+}
+
+// lengthArg parses the length argument of a length annotation
+func lengthArg(name, arg string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("@%s needs a length, got %q", name, arg)
+	}
+	return n, nil
+}
+
+// validateCharset checks that every character of value is in the @charset
+func validateCharset(value, arg string) error {
+	name := strings.ToLower(strings.TrimSpace(arg))
+	inCharset, known := charsets[name]
+	if !known {
+		return fmt.Errorf("unknown @charset %q (use hex, base64, alphanumeric or ascii)", arg)
+	}
+	// Constrained values are usually secrets, so the value is never echoed
+	if !inCharset(value) {
+		return fmt.Errorf("has characters outside the %s charset", name)
+	}
+	return nil
+}
+
+// isHex reports whether value only has hexadecimal digits
+func isHex(value string) bool {
+	for _, c := range value {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isBase64 reports whether value decodes as standard or URL-safe base64,
+// padded or not
+func isBase64(value string) bool {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if _, err := enc.DecodeString(value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlphanumeric reports whether value only has ASCII letters and digits
+func isAlphanumeric(value string) bool {
+	for _, c := range value {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCII reports whether value only has ASCII characters
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// urlValueRegex matches values that start with a URL scheme, e.g. postgres://
+var urlValueRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+
+// namingConvention expects values of keys matching a naming pattern to have a certain shape
+type namingConvention struct {
+	matches  func(key string) bool
+	valid    func(value string) bool
+	expected string
+}
+
+// namingConventions are checked in order, the first matching convention wins
+var namingConventions = []namingConvention{
+	{
+		matches:  func(key string) bool { return strings.HasSuffix(key, "_PORT") || key == "PORT" },
+		valid:    isPortValue,
+		expected: "a port number (0-65535)",
+	},
+	{
+		matches: func(key string) bool {
+			return strings.HasSuffix(key, "_URL") || strings.HasSuffix(key, "_URI") || key == "URL" || key == "URI"
+		},
+		valid:    urlValueRegex.MatchString,
+		expected: "a URL with a scheme, e.g. https://",
+	},
+	{
+		matches: func(key string) bool {
+			return strings.HasPrefix(key, "ENABLE_") || strings.HasPrefix(key, "DISABLE_") ||
+				strings.HasSuffix(key, "_ENABLED") || strings.HasSuffix(key, "_DISABLED")
+		},
+		valid: func(value string) bool {
+			_, ok := normalizeBool(value)
+			return ok
+		},
+		expected: "a boolean (true/false, 1/0, yes/no, on/off)",
+	},
+}
+
+// isPortValue reports whether value is a valid TCP/UDP port number
+func isPortValue(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port >= 0 && port <= 65535
+}
+
+// lintNamingConvention flags values that don't match what their key name
+// suggests, e.g. DATABASE_PORT=postgres://... is probably a copy-paste mistake.
+// Empty and interpolated values are skipped.
+func lintNamingConvention(parsed *parser.ParsedFile) []LintFinding {
+	findings := []LintFinding{}
+
+	for _, entry := range parsed.Entries {
+		value := strings.TrimSpace(entry.Value)
+		if value == "" || len(parser.ExtractValueRefs(value)) > 0 {
+			continue
+		}
+
+		key := strings.ToUpper(entry.Key)
+		for _, convention := range namingConventions {
+			if !convention.matches(key) {
+				continue
+			}
+			if !convention.valid(value) {
+				findings = append(findings, LintFinding{
+					Rule:     "naming-convention",
+					Severity: SeverityWarning,
+					Key:      entry.Key,
+					Line:     entry.Line,
+					Message:  fmt.Sprintf("value %q does not look like %s, which the key name suggests", value, convention.expected),
+				})
+			}
+			break
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,35 @@
+package checker
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvHeader is the column layout of CSV reports
+var csvHeader = []string{"source", "category", "key", "severity", "message"}
+
+// GenerateCSVReport renders findings as CSV with a header row. A clean run
+// produces just the header.
+func GenerateCSVReport(findings []Finding) (string, error) {
+	var out strings.Builder
+	writer := csv.NewWriter(&out)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, f := range findings {
+		record := []string{f.Source, f.Category, f.Key, f.Severity.String(), f.Message}
+		if err := writer.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return out.String(), nil
+}
@@ -1,42 +1,419 @@
 package checker
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/DuckDHD/EnvQuack/internal/parser"
 )
 
 // DiffResult represents the difference between two sets of environment variables
 type DiffResult struct {
-	Missing []string // Keys present in example but missing in env
-	Extra   []string // Keys present in env but not in example
+	Missing      []string               // Keys present in example but missing in env
+	Extra        []string               // Keys present in env but not in example
+	Optional     []string               // Env keys the example documents as optional by commenting them out
+	FromOS       []string               // Missing keys whose example references resolve in the OS environment
+	Deprecated   []Deprecation          // Keys marked @deprecated in example but still set in env
+	Invalid      []InvalidValue         // Values failing a validation annotation such as @json
+	Empty        []string               // Keys marked @required in example that are set but empty in env
+	EmptyValues  []string               // Other example keys set but empty in env (only with CompareOptions.RequireValues)
+	Changed      []ValueMismatch        // Keys whose env value differs from the example (only when comparing values)
+	Unresolved   []parser.UnresolvedRef // Env keys referencing variables set nowhere (only with CompareOptions.Expand)
+	Duplicates   []DuplicateKey         // Keys assigned more than once in the env file
+	Secrets      []SecretFinding        // Env keys that likely hold real secrets (only with CompareOptions.DetectSecrets)
+	CaseMismatch []CaseMismatch         // Env keys spelled with a different case than the example (only with CompareOptions.CaseInsensitive)
+	Origins      map[string]string      // Env file each key was last set in, when several files were merged
+	Locations    map[string]KeyLocation // Where each env key was last set, when env was read from files
+	ExampleTotal int                    // Number of keys in the example
+}
+
+// KeyLocation is the file and line an env key is set on
+type KeyLocation struct {
+	File string
+	Line int // 1-based line of the assignment
+}
+
+// Deprecation is a deprecated example key that is still set in env
+type Deprecation struct {
+	Key     string
+	Message string // Migration hint from the @deprecated annotation
+}
+
+// DuplicateKey is a key assigned on several lines of one env file; the last
+// assignment wins
+type DuplicateKey struct {
+	Key   string
+	File  string // Env file the key is duplicated in
+	Lines []int  // 1-based lines of every assignment, in file order
 }
 
 // HasIssues returns true if there are any differences
 func (d *DiffResult) HasIssues() bool {
-	return len(d.Missing) > 0 || len(d.Extra) > 0
+	return len(d.Missing) > 0 || len(d.Extra) > 0 || len(d.Invalid) > 0 || len(d.Empty) > 0 || len(d.EmptyValues) > 0 || len(d.Changed) > 0
+}
+
+// Coverage returns the percentage of example keys present in env
+func (d *DiffResult) Coverage() float64 {
+	if d.ExampleTotal == 0 {
+		return 100
+	}
+	present := d.ExampleTotal - len(d.Missing)
+	return float64(present) / float64(d.ExampleTotal) * 100
+}
+
+// CompareOptions controls how env files are compared against the example
+type CompareOptions struct {
+	Parse            *parser.ParseOptions            // How env and example files are read, nil for defaults
+	EnvTransform     KeyTransform                    // Applied to env keys before comparing, nil for none
+	ExampleTransform KeyTransform                    // Applied to example keys before comparing, nil for none
+	EnvLookup        func(key string) (string, bool) // Resolves example references (see SatisfyFromEnv), nil to disable
+	Mode             CompareMode                     // Which comparison passes run, "" for CompareKeys
+	ResolveRefs      bool                            // Expand ${VAR} references on both sides before comparing values
+	ShowValues       bool                            // Keep the values of Changed keys, masked by default (see MaskValue)
+	FileOrder        bool                            // List findings in declaration order instead of alphabetically
+	EmptyAsMissing   bool                            // Env keys set to an empty string (KEY= or KEY="") count as not set
+	Expand           bool                            // Expand ${VAR} references in env values, falling back to EnvLookup (see parser.ExpandEnvVars)
+	DetectSecrets    bool                            // Flag env values that look like real secrets (see DetectSecrets)
+	Ignore           []string                        // Key globs, e.g. GITHUB_*, never reported as missing or extra
+	CaseInsensitive  bool                            // Env keys match example keys whatever their case (see FoldKeyCase)
+	RequireValues    bool                            // Example keys set but empty in env are reported (see ApplyEmptyValues)
+	SkipEmptyExample bool                            // With RequireValues, keys the example leaves empty too are not
+}
+
+// CompareMode selects which comparison passes run
+type CompareMode string
+
+// Compare modes
+const (
+	CompareKeys   CompareMode = "keys"   // Key presence only: missing and extra variables
+	CompareValues CompareMode = "values" // Only the values of keys set on both sides
+	CompareBoth   CompareMode = "both"   // Key presence and values
+)
+
+// ComparesValues reports whether the mode compares the values of keys set
+// on both sides
+func (m CompareMode) ComparesValues() bool {
+	return m == CompareValues || m == CompareBoth
+}
+
+// ParseCompareMode validates a --compare-mode value
+func ParseCompareMode(mode string) (CompareMode, error) {
+	switch CompareMode(mode) {
+	case CompareKeys, CompareValues, CompareBoth:
+		return CompareMode(mode), nil
+	}
+	return "", fmt.Errorf("invalid compare mode %q (use keys, values or both)", mode)
+}
+
+// DefaultCompareOptions returns plain key-presence comparison
+func DefaultCompareOptions() *CompareOptions {
+	return &CompareOptions{}
 }
 
 // CompareEnvFiles compares .env file against .env.example
-func CompareEnvFiles(envFile, exampleFile string) (*DiffResult, error) {
-	env, err := parser.ParseEnvFile(envFile)
+func CompareEnvFiles(envFile, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	if opts == nil {
+		opts = DefaultCompareOptions()
+	}
+
+	parsed, err := loader.ParseEnvFileWithOptions(envFile, opts.Parse)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareParsedEnvFiles([]*parser.ParsedFile{parsed}, exampleFile, opts)
+}
+
+// CompareMergedEnvFiles merges env files, later files overriding earlier
+// ones, and compares the result against .env.example. Each key's file is
+// recorded in DiffResult.Origins.
+func CompareMergedEnvFiles(envFiles []string, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	if opts == nil {
+		opts = DefaultCompareOptions()
+	}
+
+	files := make([]*parser.ParsedFile, 0, len(envFiles))
+	origins := make(map[string]string)
+	for _, file := range envFiles {
+		parsed, err := loader.ParseEnvFileWithOptions(file, opts.Parse)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, parsed)
+		for _, key := range parsed.Keys() {
+			origins[key] = file
+		}
+	}
+
+	result, err := compareParsedEnvFiles(files, exampleFile, opts)
 	if err != nil {
 		return nil, err
 	}
+	result.Origins = origins
+	return result, nil
+}
+
+// compareParsedEnvFiles compares the merge of files, later files overriding
+// earlier ones, against .env.example. References are expanded over the
+// merged variables, so a key may use one set in another file, and duplicate
+// keys are looked for within each file.
+func compareParsedEnvFiles(files []*parser.ParsedFile, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	merged := &parser.ParsedFile{}
+	names := make([]string, 0, len(files))
+	for _, parsed := range files {
+		merged.Entries = append(merged.Entries, parsed.Entries...)
+		names = append(names, parsed.Filename)
+	}
+	merged.Filename = strings.Join(names, ", ")
+
+	var err error
+	env := merged.EnvVars()
+	unresolved := []parser.UnresolvedRef{}
+	if opts.Expand {
+		env, unresolved, err = parser.ExpandEnvVars(merged, opts.EnvLookup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := compareWithExampleFile(env, merged.Keys(), exampleFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Unresolved = unresolved
+	result.Locations = make(map[string]KeyLocation)
+	for _, parsed := range files {
+		result.Duplicates = append(result.Duplicates, FindDuplicateKeys(parsed)...)
+		for _, entry := range parsed.Entries {
+			result.Locations[entry.Key] = KeyLocation{File: parsed.Filename, Line: entry.Line}
+		}
+	}
+
+	if opts.FileOrder {
+		envPos := declarationIndex(merged.Keys())
+		sort.SliceStable(result.Unresolved, func(i, j int) bool {
+			return position(envPos, result.Unresolved[i].Key) < position(envPos, result.Unresolved[j].Key)
+		})
+		sort.SliceStable(result.Duplicates, func(i, j int) bool {
+			return result.Duplicates[i].Lines[0] < result.Duplicates[j].Lines[0]
+		})
+	} else {
+		sort.SliceStable(result.Duplicates, func(i, j int) bool {
+			return result.Duplicates[i].Key < result.Duplicates[j].Key
+		})
+	}
+	return result, nil
+}
+
+// CompareContainerEnv compares a container's environment against .env.example
+func CompareContainerEnv(container, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	env, err := parser.ParseContainerEnv(container)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareWithExampleFile(env, nil, exampleFile, opts)
+}
+
+// CompareSecretsDir compares the per-variable files of a secrets mount
+// against .env.example
+func CompareSecretsDir(dir, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	env, err := parser.ParseSecretsDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareWithExampleFile(env, nil, exampleFile, opts)
+}
+
+// CompareK8sResources compares the keys of Kubernetes ConfigMap and Secret
+// manifests against .env.example. Keys from all files are merged.
+func CompareK8sResources(files []string, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	env := make(parser.EnvVars)
+	for _, file := range files {
+		vars, err := parser.ParseK8sConfigResource(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range vars {
+			env[k] = v
+		}
+	}
+
+	return compareWithExampleFile(env, nil, exampleFile, opts)
+}
+
+// compareWithExampleFile compares env against an example file, honouring the
+// example's annotations. envOrder is the declaration order of the env keys,
+// nil when env has no meaningful order.
+func compareWithExampleFile(env parser.EnvVars, envOrder []string, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	if opts == nil {
+		opts = DefaultCompareOptions()
+	}
 
-	example, err := parser.ParseEnvFile(exampleFile)
+	// The example is documentation and never handed to docker run; its
+	// commented-out assignments document optional keys
+	exampleParse := parser.DefaultParseOptions()
+	if opts.Parse != nil {
+		*exampleParse = *opts.Parse
+	}
+	exampleParse.Docker = false
+	exampleParse.CommentedKeys = true
+
+	example, err := loader.ParseEnvFileWithOptions(exampleFile, exampleParse)
 	if err != nil {
 		return nil, err
 	}
 
-	return CompareEnvVars(env, example), nil
+	// Normalize key names before comparing
+	if opts.EnvTransform != nil {
+		env = TransformKeys(env, opts.EnvTransform)
+		for i, key := range envOrder {
+			envOrder[i] = opts.EnvTransform(key)
+		}
+	}
+	if opts.ExampleTransform != nil {
+		example.MapKeys(opts.ExampleTransform)
+	}
+
+	exampleVars := example.EnvVars()
+	caseMismatches := []CaseMismatch{}
+	if opts.CaseInsensitive {
+		env, caseMismatches = FoldKeyCase(env, exampleVars)
+		renamed := make(map[string]string, len(caseMismatches))
+		for _, m := range caseMismatches {
+			renamed[m.EnvKey] = m.Key
+		}
+		for i, key := range envOrder {
+			if to, ok := renamed[key]; ok {
+				envOrder[i] = to
+			}
+		}
+	}
+
+	if opts.EmptyAsMissing {
+		env = withoutEmptyValues(env)
+	}
+
+	result := CompareEnvVars(env, exampleVars)
+	result.CaseMismatch = caseMismatches
+	ApplyOptionalKeys(result, example.CommentedKeys())
+	ApplyDeprecations(result, env, example)
+	ApplyValidations(result, env, example)
+	ApplyRequiredValues(result, env, example)
+	if opts.RequireValues {
+		ApplyEmptyValues(result, env, exampleVars, opts.SkipEmptyExample)
+	}
+
+	if opts.Mode.ComparesValues() {
+		envValues, exampleValues := env, exampleVars
+		if opts.ResolveRefs {
+			envValues, exampleValues, err = resolveValueRefs(env, example, opts.Expand)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ApplyValueComparison(result, envValues, exampleValues)
+		if !opts.ShowValues {
+			MaskValues(result.Changed)
+		}
+	}
+
+	// Values mode only looks at keys set on both sides
+	if opts.Mode == CompareValues {
+		result.Missing = []string{}
+		result.Extra = []string{}
+		result.Optional = []string{}
+		result.FromOS = []string{}
+	}
+
+	if opts.EnvLookup != nil {
+		SatisfyFromEnv(result, exampleVars, opts.EnvLookup)
+	}
+
+	if opts.DetectSecrets {
+		result.Secrets = DetectSecrets(env)
+	}
+
+	if len(opts.Ignore) > 0 {
+		ApplyIgnore(result, opts.Ignore)
+	}
+
+	if opts.FileOrder {
+		OrderByDeclaration(result, example.Keys(), envOrder)
+	}
+
+	return result, nil
+}
+
+// FindDuplicateKeys returns the keys assigned more than once in parsed, in
+// alphabetical order. Commented-out assignments don't count.
+func FindDuplicateKeys(parsed *parser.ParsedFile) []DuplicateKey {
+	lines := make(map[string][]int)
+	for _, entry := range parsed.Entries {
+		lines[entry.Key] = append(lines[entry.Key], entry.Line)
+	}
+
+	duplicates := []DuplicateKey{}
+	for key, at := range lines {
+		if len(at) > 1 {
+			duplicates = append(duplicates, DuplicateKey{Key: key, File: parsed.Filename, Lines: at})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Key < duplicates[j].Key
+	})
+	return duplicates
+}
+
+// ApplyIgnore drops keys matching any of the glob patterns from the missing
+// and extra variables, for keys that are environment specific by design
+func ApplyIgnore(result *DiffResult, patterns []string) {
+	keep := func(keys []string) []string {
+		kept := []string{}
+		for _, key := range keys {
+			if !parser.MatchKeyPattern(key, patterns) {
+				kept = append(kept, key)
+			}
+		}
+		return kept
+	}
+
+	result.Missing = keep(result.Missing)
+	result.Extra = keep(result.Extra)
+}
+
+// withoutEmptyValues returns env without the keys set to an empty string.
+// Whitespace is a value: KEY=" " stays set.
+func withoutEmptyValues(env parser.EnvVars) parser.EnvVars {
+	set := make(parser.EnvVars, len(env))
+	for key, value := range env {
+		if value != "" {
+			set[key] = value
+		}
+	}
+	return set
 }
 
 // CompareEnvVars compares two sets of environment variables
 func CompareEnvVars(env, example parser.EnvVars) *DiffResult {
 	result := &DiffResult{
-		Missing: []string{},
-		Extra:   []string{},
+		Missing:      []string{},
+		Extra:        []string{},
+		Optional:     []string{},
+		FromOS:       []string{},
+		Deprecated:   []Deprecation{},
+		Invalid:      []InvalidValue{},
+		Empty:        []string{},
+		EmptyValues:  []string{},
+		Changed:      []ValueMismatch{},
+		Unresolved:   []parser.UnresolvedRef{},
+		Duplicates:   []DuplicateKey{},
+		Secrets:      []SecretFinding{},
+		CaseMismatch: []CaseMismatch{},
+		ExampleTotal: len(example),
 	}
 
 	// Find missing vars (in example but not in env)
@@ -59,3 +436,81 @@ func CompareEnvVars(env, example parser.EnvVars) *DiffResult {
 
 	return result
 }
+
+// ApplyOptionalKeys moves extra keys that the example documents as optional,
+// with a commented-out assignment such as `# OPTIONAL_URL=`, out of
+// result.Extra into result.Optional
+func ApplyOptionalKeys(result *DiffResult, optional []string) {
+	documented := make(map[string]bool, len(optional))
+	for _, key := range optional {
+		documented[key] = true
+	}
+
+	extra := []string{}
+	for _, key := range result.Extra {
+		if documented[key] {
+			result.Optional = append(result.Optional, key)
+		} else {
+			extra = append(extra, key)
+		}
+	}
+	result.Extra = extra
+}
+
+// SatisfyFromEnv moves missing keys out of result.Missing when their example
+// value is built from references that all resolve through lookup (usually
+// os.LookupEnv), e.g. `USERNAME=${USER}`
+func SatisfyFromEnv(result *DiffResult, example parser.EnvVars, lookup func(string) (string, bool)) {
+	stillMissing := []string{}
+
+	for _, key := range result.Missing {
+		refs := parser.ExtractValueRefs(example[key])
+		if len(refs) == 0 {
+			stillMissing = append(stillMissing, key)
+			continue
+		}
+
+		resolved := true
+		for _, ref := range refs {
+			if _, ok := lookup(ref); !ok {
+				resolved = false
+				break
+			}
+		}
+
+		if resolved {
+			result.FromOS = append(result.FromOS, key)
+		} else {
+			stillMissing = append(stillMissing, key)
+		}
+	}
+
+	result.Missing = stillMissing
+}
+
+// ApplyDeprecations records keys annotated @deprecated in the example that are
+// still set in env. Deprecated keys are never reported as missing.
+func ApplyDeprecations(result *DiffResult, env parser.EnvVars, example *parser.ParsedFile) {
+	stillMissing := []string{}
+	for _, key := range result.Missing {
+		if entry, ok := example.Lookup(key); ok && entry.Annotations.Has("deprecated") {
+			continue
+		}
+		stillMissing = append(stillMissing, key)
+	}
+	result.Missing = stillMissing
+
+	for _, entry := range example.Entries {
+		if !entry.Annotations.Has("deprecated") || !env.Has(entry.Key) {
+			continue
+		}
+		result.Deprecated = append(result.Deprecated, Deprecation{
+			Key:     entry.Key,
+			Message: entry.Annotations["deprecated"],
+		})
+	}
+
+	sort.Slice(result.Deprecated, func(i, j int) bool {
+		return result.Deprecated[i].Key < result.Deprecated[j].Key
+	})
+}
@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// writeFile writes content to name in dir and returns its path
+func writeFile(t testing.TB, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCompareMergedEnvFilesMatchesSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "A=\nB=\nC=\n")
+	first := writeFile(t, dir, ".env", "A=1\nA=2\n")
+	second := writeFile(t, dir, ".env.b", "B=${NOPE}\nC=${A}\n")
+	single := writeFile(t, dir, "one.env", "A=1\nA=2\nB=${NOPE}\nC=${A}\n")
+
+	opts := &CompareOptions{Expand: true}
+	merged, err := CompareMergedEnvFiles([]string{first, second}, example, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	one, err := CompareEnvFiles(single, example, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantUnresolved := []parser.UnresolvedRef{{Key: "B", Refs: []string{"NOPE"}}}
+	for name, result := range map[string]*DiffResult{"merged": merged, "single": one} {
+		if !reflect.DeepEqual(result.Unresolved, wantUnresolved) {
+			t.Errorf("%s: Unresolved = %v, want %v", name, result.Unresolved, wantUnresolved)
+		}
+		if len(result.Duplicates) != 1 || result.Duplicates[0].Key != "A" || !reflect.DeepEqual(result.Duplicates[0].Lines, []int{1, 2}) {
+			t.Errorf("%s: Duplicates = %v, want A on lines 1, 2", name, result.Duplicates)
+		}
+		if len(result.Missing) != 0 {
+			t.Errorf("%s: Missing = %v, want none", name, result.Missing)
+		}
+	}
+
+	if merged.Duplicates[0].File != first {
+		t.Errorf("merged duplicate file = %q, want %q", merged.Duplicates[0].File, first)
+	}
+	if merged.Origins["C"] != second {
+		t.Errorf("origin of C = %q, want %q", merged.Origins["C"], second)
+	}
+}
+
+func TestCompareMergedEnvFilesExpandsAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "URL=\n")
+	base := writeFile(t, dir, ".env", "HOST=localhost\n")
+	override := writeFile(t, dir, ".env.local", "URL=http://${HOST}/api\n")
+
+	result, err := CompareMergedEnvFiles([]string{base, override}, example, &CompareOptions{Expand: true, Mode: CompareBoth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Unresolved) != 0 {
+		t.Errorf("Unresolved = %v, want none: HOST is set in the other file", result.Unresolved)
+	}
+}
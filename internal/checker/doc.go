@@ -0,0 +1,7 @@
+// Package checker compares parsed environment sources and renders reports.
+//
+// It is safe to embed: no code path calls os.Exit, log.Fatal or log.Panic,
+// nor writes to stdout or stderr. Failures are returned as errors and exit
+// decisions as ExitStatus values; only cmd/envquack and internal/cli turn
+// them into exit codes. TestNoProcessSideEffects enforces this.
+package checker
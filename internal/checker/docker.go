@@ -16,6 +16,10 @@ type DockerfileDiffResult struct {
 	UnusedArgs         []string // ARG variables not referenced anywhere
 	HardcodedEnvs      []string // ENV variables with hardcoded values (might need to be configurable)
 	MissingArgDefaults []string // ARG variables without default values
+	Warnings           []string // Dockerfile instructions that could not be parsed (see parser.DockerfileEnvInfo.Warnings)
+
+	Stages    []string            // Build stages of the Dockerfile, in order
+	VarStages map[string][]string // Stages each variable belongs to (see parser.DockerfileEnvInfo.VarStages)
 }
 
 // HasIssues returns true if there are any issues
@@ -29,7 +33,7 @@ func (d *DockerfileDiffResult) HasIssues() bool {
 // CompareDockerfileWithEnv compares Dockerfile requirements against env files
 func CompareDockerfileWithEnv(dockerfilePath string, envFiles []string) (*DockerfileDiffResult, error) {
 	// Parse Dockerfile
-	dockerfileInfo, err := parser.ParseDockerfile(dockerfilePath)
+	dockerfileInfo, err := loader.ParseDockerfile(dockerfilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Dockerfile: %w", err)
 	}
@@ -37,7 +41,7 @@ func CompareDockerfileWithEnv(dockerfilePath string, envFiles []string) (*Docker
 	// Parse all env files
 	allEnvVars := make(parser.EnvVars)
 	for _, envFile := range envFiles {
-		envVars, err := parser.ParseEnvFile(envFile)
+		envVars, err := loader.ParseEnvFile(envFile)
 		if err != nil {
 			// Skip missing files, we'll report them separately
 			continue
@@ -60,6 +64,9 @@ func compareDockerfileWithEnvVars(dockerfileInfo *parser.DockerfileEnvInfo, envV
 		UnusedArgs:         []string{},
 		HardcodedEnvs:      []string{},
 		MissingArgDefaults: []string{},
+		Warnings:           dockerfileInfo.Warnings,
+		Stages:             dockerfileInfo.Stages,
+		VarStages:          dockerfileInfo.VarStages,
 	}
 
 	// Get all variables referenced in Dockerfile
@@ -159,93 +166,148 @@ func GenerateDockerfileReport(result *DockerfileDiffResult, opts *ReportOptions)
 	var report strings.Builder
 
 	if !result.HasIssues() {
-		report.WriteString("✅ Dockerfile environment is aligned.\n")
+		if opts.Plain {
+			report.WriteString("Dockerfile check passed: environment is aligned.\n")
+			writeDockerfileWarnings(&report, result.Warnings, opts)
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + "Dockerfile environment is aligned.\n")
 		if opts.ShowDuck {
 			report.WriteString("(Your gopher-duck approves of your containerized setup!)\n")
 		}
+		writeDockerfileWarnings(&report, result.Warnings, opts)
 		return report.String()
 	}
 
 	// Header with duck
-	if opts.ShowDuck {
-		report.WriteString(quack.GetAngryDuck() + "\n")
-		report.WriteString("QUACK! 🦆 Dockerfile environment issues detected:\n\n")
+	if opts.Plain {
+		report.WriteString(fmt.Sprintf("Dockerfile check failed: %d missing, %d unused ARG, %d hardcoded ENV, %d unused\n\n",
+			len(result.MissingInEnv), len(result.UnusedArgs), len(result.HardcodedEnvs), len(result.ExtraInEnv)))
+	} else if opts.ShowDuck {
+		missing := len(result.MissingInEnv) + len(result.UnusedArgs) + len(result.HardcodedEnvs)
+		report.WriteString(quack.GetDuckForSeverity(missing, len(result.ExtraInEnv)) + "\n")
+		report.WriteString("QUACK! " + emoji(opts, "🦆") + "Dockerfile environment issues detected:\n\n")
 	}
 
-	// Missing variables
-	if len(result.MissingInEnv) > 0 {
-		if opts.Colorize {
-			report.WriteString("🔴 Variables required by Dockerfile but missing in env files:\n")
-		} else {
-			report.WriteString("Missing variables:\n")
-		}
+	// Multi-stage Dockerfiles get their findings by stage
+	if len(result.Stages) > 1 {
+		writeStageFindings(&report, result, opts)
+	} else {
+		writeDockerfileFindings(&report, result, opts)
+	}
 
-		for _, key := range result.MissingInEnv {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
-		}
+	// Extra variables (usually less critical)
+	if len(result.ExtraInEnv) > 0 {
+		writeHeading(&report, opts, "🔵", "Variables in env files but not used in Dockerfile", "Unused variables")
+
+		writeKeyList(&report, result.ExtraInEnv, "  ", opts)
 		report.WriteString("\n")
 	}
 
-	// Unused ARG variables
-	if len(result.UnusedArgs) > 0 {
-		if opts.Colorize {
-			report.WriteString("🟠 ARG variables declared but never used:\n")
-		} else {
-			report.WriteString("Unused ARG variables:\n")
-		}
+	writeDockerfileWarnings(&report, result.Warnings, opts)
 
-		for _, key := range result.UnusedArgs {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
+	// Footer with duck message
+	if opts.ShowDuck && !opts.Plain {
+		report.WriteString("(Your gopher-duck is confused by your Dockerfile setup!)\n")
+	}
+
+	return report.String()
+}
+
+// writeDockerfileWarnings lists the Dockerfile instructions the parser skipped
+func writeDockerfileWarnings(report *strings.Builder, warnings []string, opts *ReportOptions) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	writeHeading(report, opts, "⚠️ ", "Dockerfile instructions that could not be parsed", "Unparsed instructions")
+	writeKeyList(report, warnings, "  ", opts)
+	report.WriteString("\n")
+}
+
+// stageFindings describe the Dockerfile findings listed under each build stage
+var stageFindings = []struct {
+	format  string
+	keys    func(*DockerfileDiffResult) []string
+	verbose bool // Only listed with --verbose, like the flat report
+}{
+	{"%s missing in env files", func(d *DockerfileDiffResult) []string { return d.MissingInEnv }, false},
+	{"unused ARG %s", func(d *DockerfileDiffResult) []string { return d.UnusedArgs }, false},
+	{"hardcoded ENV %s", func(d *DockerfileDiffResult) []string { return d.HardcodedEnvs }, true},
+	{"ARG %s has no default", func(d *DockerfileDiffResult) []string { return d.MissingArgDefaults }, true},
+}
+
+// writeStageFindings lists the findings of a multi-stage Dockerfile under
+// the stage each variable appears in, e.g. "Stage builder:" then
+// "- unused ARG FOO". ARGs declared before the first FROM are global.
+func writeStageFindings(report *strings.Builder, result *DockerfileDiffResult, opts *ReportOptions) {
+	groups := make(map[string][]string)
+	for _, finding := range stageFindings {
+		if finding.verbose && !opts.Verbose {
+			continue
+		}
+		for _, key := range finding.keys(result) {
+			line := fmt.Sprintf(finding.format, key)
+			stages := result.VarStages[key]
+			if len(stages) == 0 {
+				groups[""] = append(groups[""], line)
+			}
+			for _, stage := range stages {
+				groups[stage] = append(groups[stage], line)
+			}
 		}
-		report.WriteString("\n")
 	}
 
-	// Hardcoded ENV variables (warnings)
-	if len(result.HardcodedEnvs) > 0 && opts.Verbose {
-		if opts.Colorize {
-			report.WriteString("🟡 ENV variables with hardcoded values (consider making configurable):\n")
-		} else {
-			report.WriteString("Hardcoded ENV variables:\n")
+	for _, stage := range append([]string{""}, result.Stages...) {
+		lines := groups[stage]
+		if len(lines) == 0 {
+			continue
 		}
 
-		for _, key := range result.HardcodedEnvs {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
+		title := "Stage " + stage + ":"
+		if stage == "" {
+			title = "Global ARGs (before the first FROM):"
 		}
+		if opts.Colorize {
+			title = emoji(opts, "🏗️ ") + title
+		}
+		report.WriteString(title + "\n")
+		writeKeyList(report, lines, "  ", opts)
 		report.WriteString("\n")
 	}
+}
 
-	// ARG variables without defaults
-	if len(result.MissingArgDefaults) > 0 && opts.Verbose {
-		if opts.Colorize {
-			report.WriteString("⚠️  ARG variables without default values:\n")
-		} else {
-			report.WriteString("ARG variables without defaults:\n")
-		}
+// writeDockerfileFindings lists the findings of a single-stage Dockerfile by category
+func writeDockerfileFindings(report *strings.Builder, result *DockerfileDiffResult, opts *ReportOptions) {
+	// Missing variables
+	if len(result.MissingInEnv) > 0 {
+		writeHeading(report, opts, "🔴", "Variables required by Dockerfile but missing in env files", "Missing variables")
 
-		for _, key := range result.MissingArgDefaults {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
-		}
+		writeKeyList(report, result.MissingInEnv, "  ", opts)
 		report.WriteString("\n")
 	}
 
-	// Extra variables (usually less critical)
-	if len(result.ExtraInEnv) > 0 {
-		if opts.Colorize {
-			report.WriteString("🔵 Variables in env files but not used in Dockerfile:\n")
-		} else {
-			report.WriteString("Unused variables:\n")
-		}
+	// Unused ARG variables
+	if len(result.UnusedArgs) > 0 {
+		writeHeading(report, opts, "🟠", "ARG variables declared but never used", "Unused ARG variables")
 
-		for _, key := range result.ExtraInEnv {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
-		}
+		writeKeyList(report, result.UnusedArgs, "  ", opts)
 		report.WriteString("\n")
 	}
 
-	// Footer with duck message
-	if opts.ShowDuck {
-		report.WriteString("(Your gopher-duck is confused by your Dockerfile setup!)\n")
+	// Hardcoded ENV variables (warnings)
+	if len(result.HardcodedEnvs) > 0 && opts.Verbose {
+		writeHeading(report, opts, "🟡", "ENV variables with hardcoded values (consider making configurable)", "Hardcoded ENV variables")
+
+		writeKeyList(report, result.HardcodedEnvs, "  ", opts)
+		report.WriteString("\n")
 	}
 
-	return report.String()
+	// ARG variables without defaults
+	if len(result.MissingArgDefaults) > 0 && opts.Verbose {
+		writeHeading(report, opts, "⚠️ ", "ARG variables without default values", "ARG variables without defaults")
+
+		writeKeyList(report, result.MissingArgDefaults, "  ", opts)
+		report.WriteString("\n")
+	}
 }
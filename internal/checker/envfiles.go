@@ -0,0 +1,140 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// SharedEnvFile is an env_file referenced by more than one compose service
+type SharedEnvFile struct {
+	File      string
+	Services  []string          // Sorted by name
+	Conflicts []EnvFileConflict // Keys of the file the services end up with different values for
+}
+
+// EnvFileConflict is a key of a shared env_file that some services override,
+// so they no longer agree on its value. Values are never recorded.
+type EnvFileConflict struct {
+	Key     string
+	Sources map[string]string // Where each service's value comes from: the env_file providing it, or "environment"
+}
+
+// findSharedEnvFiles lists the env_files several services reference. For each
+// key such a file provides, a service's effective value follows compose
+// precedence: a later env_file of the service overrides an earlier one, and
+// the service's environment overrides every env_file. Keys whose effective
+// value differs between the sharing services are conflicts. Env files that
+// can't be read are skipped (they are reported as missing separately).
+func findSharedEnvFiles(composeInfo *parser.ComposeEnvInfo) []SharedEnvFile {
+	shared := []SharedEnvFile{}
+
+	// Contents of every env_file, read once
+	contents := make(map[string]parser.EnvVars)
+	read := func(envFile string) parser.EnvVars {
+		if vars, done := contents[envFile]; done {
+			return vars
+		}
+		vars, err := loader.ParseEnvFile(envFile)
+		if err != nil {
+			vars = nil
+		}
+		contents[envFile] = vars
+		return vars
+	}
+
+	for envFile, services := range composeInfo.EnvFileServices() {
+		if len(services) < 2 {
+			continue
+		}
+		sort.Strings(services)
+		entry := SharedEnvFile{File: envFile, Services: services, Conflicts: []EnvFileConflict{}}
+
+		vars := read(envFile)
+		keys := vars.GetKeys()
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			values := make(map[string]bool)
+			sources := make(map[string]string, len(services))
+
+			for _, service := range services {
+				value, source := vars[key], envFile
+				after := false
+				for _, other := range composeInfo.ServiceEnvFiles[service] {
+					if other == envFile {
+						after = true
+						continue
+					}
+					if otherValue, ok := read(other)[key]; ok && after {
+						value, source = otherValue, other
+					}
+				}
+				if inline, ok := composeInfo.ServiceVars[service][key]; ok {
+					value, source = inline, "environment"
+				}
+
+				values[value] = true
+				sources[service] = source
+			}
+
+			if len(values) > 1 {
+				entry.Conflicts = append(entry.Conflicts, EnvFileConflict{Key: key, Sources: sources})
+			}
+		}
+
+		shared = append(shared, entry)
+	}
+
+	sort.Slice(shared, func(i, j int) bool {
+		return shared[i].File < shared[j].File
+	})
+	return shared
+}
+
+// describe lists each service with where its value comes from, e.g.
+// "api: environment, worker: .env.shared"
+func (c EnvFileConflict) describe() string {
+	services := make([]string, 0, len(c.Sources))
+	for service := range c.Sources {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	parts := make([]string, 0, len(services))
+	for _, service := range services {
+		parts = append(parts, service+": "+c.Sources[service])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GenerateSharedEnvFilesReport lists env_files shared by several services and
+// the keys they end up disagreeing on. With showAll unset, only files with
+// conflicts are listed. The report is informational.
+func GenerateSharedEnvFilesReport(shared []SharedEnvFile, showAll bool, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+	for _, file := range shared {
+		if len(file.Conflicts) == 0 && !showAll {
+			continue
+		}
+
+		if report.Len() == 0 {
+			writeHeading(&report, opts, "🔗", "env_files shared by several services", "Shared env_files")
+		}
+
+		report.WriteString(fmt.Sprintf("  %s (%s)\n", file.File, strings.Join(file.Services, ", ")))
+		lines := make([]string, 0, len(file.Conflicts))
+		for _, conflict := range file.Conflicts {
+			lines = append(lines, fmt.Sprintf("%s differs between services (%s)", conflict.Key, conflict.describe()))
+		}
+		writeKeyList(&report, lines, "    ", opts)
+	}
+
+	return report.String()
+}
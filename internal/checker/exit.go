@@ -0,0 +1,182 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExitReason categorizes why a run failed
+type ExitReason string
+
+// Exit reasons reported in structured output
+const (
+	ExitReasonNone             ExitReason = "none"
+	ExitReasonMissingRequired  ExitReason = "missing_required"
+	ExitReasonStrictExtra      ExitReason = "strict_extra"
+	ExitReasonValidationFailed ExitReason = "validation_failed"
+	ExitReasonValueMismatch    ExitReason = "value_mismatch"
+)
+
+// ExitStatus is the exit decision for a run
+type ExitStatus struct {
+	Code   int
+	Reason ExitReason
+}
+
+// OK returns true if the run should exit successfully
+func (e ExitStatus) OK() bool {
+	return e.Code == 0
+}
+
+// Finding categories an ExitPolicy can fail on
+const (
+	FailOnMissing = "missing" // Missing, or required but empty, variables, and empty values with RequireValues
+	FailOnInvalid = "invalid" // Values failing a validation annotation
+	FailOnChanged = "changed" // Values differing from the example
+	FailOnExtra   = "extra"   // Extra variables, when shown
+)
+
+// FailOnCategories lists every category, in precedence order
+var FailOnCategories = []string{FailOnMissing, FailOnInvalid, FailOnChanged, FailOnExtra}
+
+// ExitPolicy decides which findings fail a run
+type ExitPolicy struct {
+	ShowExtra  bool     // Extra variables are reported and judged; hidden by default
+	AllowExtra bool     // Shown extra variables are warnings instead of failures
+	FailOn     []string // Categories that fail the run, nil for all; the rest are warnings
+}
+
+// Fails reports whether findings of category fail the run
+func (p *ExitPolicy) Fails(category string) bool {
+	if p.FailOn == nil {
+		return true
+	}
+	for _, c := range p.FailOn {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFailOn validates a --fail-on list of categories
+func ParseFailOn(categories []string) ([]string, error) {
+	parsed := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		known := false
+		for _, c := range FailOnCategories {
+			known = known || c == category
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid --fail-on category %q (use %s)", category, strings.Join(FailOnCategories, ", "))
+		}
+		parsed = append(parsed, category)
+	}
+	return parsed, nil
+}
+
+// DefaultExitPolicy returns the default policy: missing variables fail, and
+// extra variables are hidden since local env files often add their own
+func DefaultExitPolicy() *ExitPolicy {
+	return &ExitPolicy{}
+}
+
+// visibleResult returns the result as the policy presents it, without extra
+// variables unless they are shown. The result itself is left untouched.
+func visibleResult(result *DiffResult, policy *ExitPolicy) *DiffResult {
+	if policy != nil && policy.ShowExtra {
+		return result
+	}
+	visible := *result
+	visible.Extra = []string{}
+	return &visible
+}
+
+// DecideExit determines the exit code and reason for an env comparison.
+// Missing (or required but empty, or empty) variables take precedence over invalid
+// values, those over differing values, and those over extra ones. Categories
+// the policy does not fail on are skipped.
+func DecideExit(result *DiffResult, policy *ExitPolicy) ExitStatus {
+	if policy == nil {
+		policy = DefaultExitPolicy()
+	}
+
+	switch {
+	case (len(result.Missing) > 0 || len(result.Empty) > 0 || len(result.EmptyValues) > 0) && policy.Fails(FailOnMissing):
+		return ExitStatus{Code: 1, Reason: ExitReasonMissingRequired}
+	case len(result.Invalid) > 0 && policy.Fails(FailOnInvalid):
+		return ExitStatus{Code: 1, Reason: ExitReasonValidationFailed}
+	case len(result.Changed) > 0 && policy.Fails(FailOnChanged):
+		return ExitStatus{Code: 1, Reason: ExitReasonValueMismatch}
+	case len(result.Extra) > 0 && policy.ShowExtra && !policy.AllowExtra && policy.Fails(FailOnExtra):
+		return ExitStatus{Code: 1, Reason: ExitReasonStrictExtra}
+	}
+	return ExitStatus{Code: 0, Reason: ExitReasonNone}
+}
+
+// findingFailOn maps finding categories onto the --fail-on category that
+// fails the run on them
+var findingFailOn = map[string]string{
+	"missing":        FailOnMissing,
+	"required_empty": FailOnMissing,
+	"empty_value":    FailOnMissing,
+	"undocumented":   FailOnMissing,
+	"invalid":        FailOnInvalid,
+	"changed":        FailOnChanged,
+	"extra":          FailOnExtra,
+}
+
+// failOnReasons is the exit reason of each --fail-on category
+var failOnReasons = map[string]ExitReason{
+	FailOnMissing: ExitReasonMissingRequired,
+	FailOnInvalid: ExitReasonValidationFailed,
+	FailOnChanged: ExitReasonValueMismatch,
+	FailOnExtra:   ExitReasonStrictExtra,
+}
+
+// DecideFindingsExit determines the exit code for a check that reports
+// normalized findings, with the same precedence as DecideExit. Only errors
+// fail the run, and only in a category the policy fails on; undocumented
+// variables count as missing from the example.
+func DecideFindingsExit(findings []Finding, policy *ExitPolicy) ExitStatus {
+	if policy == nil {
+		policy = DefaultExitPolicy()
+	}
+
+	failing := make(map[string]bool)
+	for _, f := range findings {
+		if category, ok := findingFailOn[f.Category]; ok && f.Severity == SeverityError {
+			failing[category] = true
+		}
+	}
+
+	for _, category := range FailOnCategories {
+		if failing[category] && policy.Fails(category) {
+			return ExitStatus{Code: 1, Reason: failOnReasons[category]}
+		}
+	}
+	return ExitStatus{Code: 0, Reason: ExitReasonNone}
+}
+
+// Verdict is the overall mood of a run, shown as the duck and headline
+type Verdict int
+
+// Verdicts, from best to worst
+const (
+	VerdictHappy   Verdict = iota // Nothing to report
+	VerdictContent                // Only findings the policy tolerates
+	VerdictAngry                  // Findings that fail the run
+)
+
+// DecideVerdict derives the verdict from the same policy as the exit code,
+// so tolerated findings never show the angry duck
+func DecideVerdict(result *DiffResult, policy *ExitPolicy) Verdict {
+	switch {
+	case !DecideExit(result, policy).OK():
+		return VerdictAngry
+	case visibleResult(result, policy).HasIssues() || len(result.Deprecated) > 0 || len(result.Unresolved) > 0 || len(result.Duplicates) > 0 || len(result.Secrets) > 0 || len(result.CaseMismatch) > 0:
+		return VerdictContent
+	}
+	return VerdictHappy
+}
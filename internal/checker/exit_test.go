@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecideExit(t *testing.T) {
+	missing := &DiffResult{Missing: []string{"A"}}
+	extra := &DiffResult{Extra: []string{"B"}}
+	changed := &DiffResult{Changed: []ValueMismatch{{Key: "C", Expected: "1", Actual: "2"}}}
+	mixed := &DiffResult{Missing: []string{"A"}, Extra: []string{"B"}}
+
+	tests := []struct {
+		name   string
+		result *DiffResult
+		policy *ExitPolicy
+		want   ExitStatus
+	}{
+		{"clean", &DiffResult{}, nil, ExitStatus{0, ExitReasonNone}},
+		{"missing", missing, nil, ExitStatus{1, ExitReasonMissingRequired}},
+		{"extra hidden", extra, nil, ExitStatus{0, ExitReasonNone}},
+		{"extra shown", extra, &ExitPolicy{ShowExtra: true}, ExitStatus{1, ExitReasonStrictExtra}},
+		{"extra allowed", extra, &ExitPolicy{ShowExtra: true, AllowExtra: true}, ExitStatus{0, ExitReasonNone}},
+		{"changed", changed, nil, ExitStatus{1, ExitReasonValueMismatch}},
+		{"missing not failing", missing, &ExitPolicy{FailOn: []string{FailOnExtra}}, ExitStatus{0, ExitReasonNone}},
+		{"extra not failing", extra, &ExitPolicy{ShowExtra: true, FailOn: []string{FailOnMissing}}, ExitStatus{0, ExitReasonNone}},
+		{"changed not failing", changed, &ExitPolicy{FailOn: []string{FailOnMissing, FailOnExtra}}, ExitStatus{0, ExitReasonNone}},
+		{"mixed fails on missing", mixed, &ExitPolicy{ShowExtra: true}, ExitStatus{1, ExitReasonMissingRequired}},
+		{"mixed fails on extra only", mixed, &ExitPolicy{ShowExtra: true, FailOn: []string{FailOnExtra}}, ExitStatus{1, ExitReasonStrictExtra}},
+		{"empty fail-on fails nothing", mixed, &ExitPolicy{ShowExtra: true, FailOn: []string{}}, ExitStatus{0, ExitReasonNone}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecideExit(tt.result, tt.policy); got != tt.want {
+				t.Errorf("DecideExit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		input   []string
+		want    []string
+		wantErr bool
+	}{
+		{[]string{"missing", "extra"}, []string{"missing", "extra"}, false},
+		{[]string{" Changed "}, []string{"changed"}, false},
+		{[]string{"missing", "typo"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFailOn(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFailOn(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFailOn(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestVisibleFindings(t *testing.T) {
+	result := &DiffResult{Missing: []string{"A"}, Extra: []string{"B"}}
+
+	tests := []struct {
+		name   string
+		policy *ExitPolicy
+		want   []string
+	}{
+		{"default hides extra", nil, []string{"missing A"}},
+		{"extra hidden", &ExitPolicy{}, []string{"missing A"}},
+		{"extra shown", &ExitPolicy{ShowExtra: true}, []string{"missing A", "extra B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, f := range result.VisibleFindings(tt.policy) {
+				got = append(got, f.Category+" "+f.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VisibleFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if len(result.Extra) != 1 {
+		t.Errorf("VisibleFindings() changed the result: Extra = %v", result.Extra)
+	}
+}
+
+func TestDecideFindingsExit(t *testing.T) {
+	undocumented := []Finding{{SourceRefs, "undocumented", "A", SeverityError, ""}}
+	info := []Finding{{SourceRefs, "has_default", "B", SeverityInfo, ""}}
+	mixed := []Finding{{SourceEnv, "changed", "C", SeverityError, ""}, {SourceEnv, "missing", "A", SeverityError, ""}}
+
+	tests := []struct {
+		name     string
+		findings []Finding
+		policy   *ExitPolicy
+		want     ExitStatus
+	}{
+		{"clean", nil, nil, ExitStatus{0, ExitReasonNone}},
+		{"undocumented", undocumented, nil, ExitStatus{1, ExitReasonMissingRequired}},
+		{"undocumented not failing", undocumented, &ExitPolicy{FailOn: []string{FailOnChanged}}, ExitStatus{0, ExitReasonNone}},
+		{"info never fails", info, nil, ExitStatus{0, ExitReasonNone}},
+		{"missing takes precedence", mixed, nil, ExitStatus{1, ExitReasonMissingRequired}},
+		{"changed only", mixed, &ExitPolicy{FailOn: []string{FailOnChanged}}, ExitStatus{1, ExitReasonValueMismatch}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecideFindingsExit(tt.findings, tt.policy); got != tt.want {
+				t.Errorf("DecideFindingsExit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Finding sources
+const (
+	SourceEnv        = "env"
+	SourceCompose    = "compose"
+	SourceDockerfile = "dockerfile"
+	SourceRefs       = "refs"
+	SourcePackage    = "package_json"
+	SourceMakefile   = "makefile"
+	SourceOpenAPI    = "openapi"
+	SourceImage      = "image"
+)
+
+// Finding is a single issue in a format-independent shape, used by the
+// structured renderers
+type Finding struct {
+	Source   string   // Which check produced the finding
+	Category string   // Kind of finding, e.g. "missing" or "extra"
+	Key      string   // Variable (or file) the finding is about
+	Severity Severity // How serious the finding is
+	Message  string   // Human readable explanation
+}
+
+// Findings flattens an env comparison into normalized findings
+func (d *DiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range d.Missing {
+		findings = append(findings, Finding{SourceEnv, "missing", key, SeverityError,
+			fmt.Sprintf("%s is present in .env.example but missing in .env", key)})
+	}
+	for _, key := range d.Empty {
+		findings = append(findings, Finding{SourceEnv, "required_empty", key, SeverityError,
+			fmt.Sprintf("%s is marked @required in .env.example but empty in .env", key)})
+	}
+	for _, key := range d.EmptyValues {
+		findings = append(findings, Finding{SourceEnv, "empty_value", key, SeverityError,
+			fmt.Sprintf("%s is set in .env but has no value", key)})
+	}
+	for _, key := range d.Extra {
+		findings = append(findings, Finding{SourceEnv, "extra", key, SeverityWarning,
+			fmt.Sprintf("%s is present in .env but not documented in .env.example", key)})
+	}
+	for _, dep := range d.Deprecated {
+		message := fmt.Sprintf("%s is deprecated but still set in .env", dep.Key)
+		if dep.Message != "" {
+			message += ": " + dep.Message
+		}
+		findings = append(findings, Finding{SourceEnv, "deprecated", dep.Key, SeverityWarning, message})
+	}
+	for _, secret := range d.Secrets {
+		findings = append(findings, Finding{SourceEnv, "possible_secret", secret.Key, SeverityWarning,
+			fmt.Sprintf("%s likely holds a real secret (%s)", secret.Key, strings.Join(secret.Reasons, ", "))})
+	}
+	for _, m := range d.CaseMismatch {
+		findings = append(findings, Finding{SourceEnv, "case_mismatch", m.Key, SeverityWarning,
+			fmt.Sprintf("%s is spelled %s in .env", m.Key, m.EnvKey)})
+	}
+	for _, dup := range d.Duplicates {
+		findings = append(findings, Finding{SourceEnv, "duplicate_key", dup.Key, SeverityWarning,
+			fmt.Sprintf("%s is set on lines %s of .env; the last value wins", dup.Key, formatLines(dup.Lines))})
+	}
+	for _, u := range d.Unresolved {
+		findings = append(findings, Finding{SourceEnv, "unresolved_ref", u.Key, SeverityWarning,
+			fmt.Sprintf("%s references %s, which is set neither in .env nor in the environment", u.Key, formatRefs(u.Refs))})
+	}
+	for _, c := range d.Changed {
+		findings = append(findings, Finding{SourceEnv, "changed", c.Key, SeverityError,
+			fmt.Sprintf("%s is %q in .env but %q in .env.example", c.Key, c.Actual, c.Expected)})
+	}
+	for _, inv := range d.Invalid {
+		findings = append(findings, Finding{SourceEnv, "invalid", inv.Key, SeverityError,
+			fmt.Sprintf("%s fails @%s: %s", inv.Key, inv.Annotation, inv.Message)})
+	}
+
+	return findings
+}
+
+// VisibleFindings flattens an env comparison as the policy presents it,
+// without extra variables unless they are shown
+func (d *DiffResult) VisibleFindings(policy *ExitPolicy) []Finding {
+	return visibleResult(d, policy).Findings()
+}
+
+// Findings flattens a compose comparison into normalized findings
+func (c *ComposeDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, file := range c.MissingEnvFiles {
+		findings = append(findings, Finding{SourceCompose, "missing_env_file", file, SeverityError,
+			fmt.Sprintf("env_file %s is referenced in compose but does not exist", file)})
+	}
+	for _, file := range c.UnignoredSecretFiles {
+		findings = append(findings, Finding{SourceCompose, "secret_not_gitignored", file, SeverityError,
+			fmt.Sprintf("env_file %s contains secret-looking values but is not covered by .gitignore", file)})
+	}
+	for _, file := range c.SecretFilesInBuild {
+		findings = append(findings, Finding{SourceCompose, "secret_not_dockerignored", file, SeverityError,
+			fmt.Sprintf("env_file %s contains secret-looking values but is not excluded by .dockerignore, so builds can copy it into images", file)})
+	}
+	for _, key := range c.MissingInEnv {
+		message := fmt.Sprintf("%s is required by compose but missing in env files", key)
+		if where := c.locationSummary(key); where != "" {
+			message += " (" + where + ")"
+		}
+		findings = append(findings, Finding{SourceCompose, "missing", key, SeverityError, message})
+	}
+	for _, key := range c.ExtraInEnv {
+		findings = append(findings, Finding{SourceCompose, "unused", key, SeverityLow,
+			fmt.Sprintf("%s is set in env files but not used in compose", key)})
+	}
+	for _, service := range c.UnconfiguredServices {
+		findings = append(findings, Finding{SourceCompose, "no_env_config", service, SeverityInfo,
+			fmt.Sprintf("service %s declares neither environment nor env_file", service)})
+	}
+	for _, file := range c.SharedEnvFiles {
+		findings = append(findings, Finding{SourceCompose, "shared_env_file", file.File, SeverityInfo,
+			fmt.Sprintf("env_file %s is shared by services %s", file.File, strings.Join(file.Services, ", "))})
+		for _, conflict := range file.Conflicts {
+			findings = append(findings, Finding{SourceCompose, "env_file_conflict", conflict.Key, SeverityInfo,
+				fmt.Sprintf("%s from shared env_file %s differs between services (%s)", conflict.Key, file.File, conflict.describe())})
+		}
+	}
+
+	return findings
+}
+
+// Findings flattens a Dockerfile comparison into normalized findings
+func (d *DockerfileDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range d.MissingInEnv {
+		findings = append(findings, Finding{SourceDockerfile, "missing", key, SeverityError,
+			fmt.Sprintf("%s is required by the Dockerfile but missing in env files", key)})
+	}
+	for _, key := range d.UnusedArgs {
+		findings = append(findings, Finding{SourceDockerfile, "unused_arg", key, SeverityWarning,
+			fmt.Sprintf("ARG %s is declared but never used", key)})
+	}
+	for _, key := range d.HardcodedEnvs {
+		findings = append(findings, Finding{SourceDockerfile, "hardcoded_env", key, SeverityLow,
+			fmt.Sprintf("ENV %s has a hardcoded value; consider making it configurable", key)})
+	}
+	for _, key := range d.MissingArgDefaults {
+		findings = append(findings, Finding{SourceDockerfile, "arg_without_default", key, SeverityInfo,
+			fmt.Sprintf("ARG %s has no default value", key)})
+	}
+	for _, key := range d.ExtraInEnv {
+		findings = append(findings, Finding{SourceDockerfile, "unused", key, SeverityLow,
+			fmt.Sprintf("%s is set in env files but not used in the Dockerfile", key)})
+	}
+
+	return findings
+}
+
+// Findings flattens a reference scan into normalized findings
+func (r *RefsDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range r.Undocumented {
+		findings = append(findings, Finding{SourceRefs, "undocumented", key, SeverityError,
+			fmt.Sprintf("%s is referenced in the scanned files but missing in .env.example", key)})
+	}
+
+	return findings
+}
+
+// Findings flattens a package.json scripts check into normalized findings
+func (p *PackageScriptsDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range p.Undocumented {
+		findings = append(findings, Finding{SourcePackage, "undocumented", key, SeverityError,
+			fmt.Sprintf("%s is used by package.json scripts but missing in .env.example", key)})
+	}
+	for _, key := range p.InlineOnly {
+		findings = append(findings, Finding{SourcePackage, "inline_only", key, SeverityInfo,
+			fmt.Sprintf("%s is only assigned inline in package.json scripts", key)})
+	}
+
+	return findings
+}
+
+// Findings flattens a Makefile check into normalized findings
+func (m *MakefileDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range m.Undocumented {
+		findings = append(findings, Finding{SourceMakefile, "undocumented", key, SeverityError,
+			fmt.Sprintf("%s is taken from the environment by the Makefile but missing in .env.example", key)})
+	}
+	for _, key := range m.WithDefault {
+		findings = append(findings, Finding{SourceMakefile, "has_default", key, SeverityInfo,
+			fmt.Sprintf("%s has a ?= default in the Makefile but is missing in .env.example", key)})
+	}
+
+	return findings
+}
+
+// Findings flattens an OpenAPI servers check into normalized findings
+func (o *OpenAPIDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range o.Undocumented {
+		findings = append(findings, Finding{SourceOpenAPI, "undocumented", key, SeverityError,
+			fmt.Sprintf("%s fills an OpenAPI server URL but is missing in .env.example", key)})
+	}
+	for _, key := range o.WithDefault {
+		findings = append(findings, Finding{SourceOpenAPI, "has_default", key, SeverityInfo,
+			fmt.Sprintf("%s has a default in the OpenAPI servers but is missing in .env.example", key)})
+	}
+
+	return findings
+}
+
+// Findings flattens an image check into normalized findings. They are all
+// informational, and image-only variables, mostly base image noise like
+// PATH, are left out.
+func (i *ImageDiffResult) Findings() []Finding {
+	findings := []Finding{}
+
+	for _, key := range i.Runtime {
+		findings = append(findings, Finding{SourceImage, "runtime", key, SeverityInfo,
+			fmt.Sprintf("%s must be supplied at runtime, image %s does not set it", key, i.Image)})
+	}
+	for _, key := range i.BakedIn {
+		findings = append(findings, Finding{SourceImage, "baked_in", key, SeverityInfo,
+			fmt.Sprintf("%s is baked into image %s with ENV", key, i.Image)})
+	}
+
+	return findings
+}
@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// githubTitles are the annotation titles of each finding category
+var githubTitles = map[string]string{
+	"missing":         "Missing env var",
+	"extra":           "Undocumented env var",
+	"required_empty":  "Required env var is empty",
+	"empty_value":     "Empty env var",
+	"invalid":         "Invalid env var",
+	"changed":         "Changed env var",
+	"deprecated":      "Deprecated env var",
+	"possible_secret": "Possible secret",
+	"duplicate_key":   "Duplicate env var",
+	"case_mismatch":   "Env var case mismatch",
+	"unresolved_ref":  "Unresolved reference",
+	"undocumented":    "Env var missing in example",
+}
+
+// githubCommand maps a severity onto GitHub's error, warning and notice
+func githubCommand(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "notice"
+}
+
+// githubEscaper escapes annotation messages, githubPropertyEscaper the
+// file= and title= properties, which also can't hold : or ,
+var (
+	githubEscaper         = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// GenerateGitHubReport renders located findings as GitHub Actions workflow
+// commands, one annotation per line in finding order, e.g.
+//
+//	::error file=.env.example,line=3,title=Missing env var::DATABASE_URL is required by .env.example
+//
+// Missing keys are errors, extra keys warnings. A clean run produces no output.
+func GenerateGitHubReport(findings []LocatedFinding) string {
+	var report strings.Builder
+
+	for _, f := range findings {
+		title, ok := githubTitles[f.Category]
+		if !ok {
+			title = f.Category
+		}
+
+		message := f.Message
+		if f.Category == "missing" {
+			message = fmt.Sprintf("%s is required by %s", f.Key, f.File)
+		}
+
+		properties := []string{}
+		if f.File != "" {
+			properties = append(properties, "file="+githubPropertyEscaper.Replace(f.File))
+		}
+		if f.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", f.Line))
+		}
+		properties = append(properties, "title="+githubPropertyEscaper.Replace(title))
+
+		report.WriteString(fmt.Sprintf("::%s %s::%s\n", githubCommand(f.Severity), strings.Join(properties, ","), githubEscaper.Replace(message)))
+	}
+
+	return report.String()
+}
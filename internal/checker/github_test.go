@@ -0,0 +1,127 @@
+package checker
+
+import "testing"
+
+func TestGenerateGitHubReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []LocatedFinding
+		want     string
+	}{
+		{
+			name: "clean run",
+			want: "",
+		},
+		{
+			name: "missing without location",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "missing", "DATABASE_URL", SeverityError, "DATABASE_URL is missing"}, File: ".env.example"},
+			},
+			want: "::error file=.env.example,title=Missing env var::DATABASE_URL is required by .env.example\n",
+		},
+		{
+			name: "missing with line",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "missing", "DATABASE_URL", SeverityError, "DATABASE_URL is missing"}, File: ".env.example", Line: 3},
+			},
+			want: "::error file=.env.example,line=3,title=Missing env var::DATABASE_URL is required by .env.example\n",
+		},
+		{
+			name: "extra is a warning",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "extra", "DEBUG", SeverityWarning, "DEBUG is set in .env but not documented"}, File: ".env", Line: 7},
+			},
+			want: "::warning file=.env,line=7,title=Undocumented env var::DEBUG is set in .env but not documented\n",
+		},
+		{
+			name: "info is a notice",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "optional", "CACHE_URL", SeverityInfo, "CACHE_URL is optional"}},
+			},
+			want: "::notice title=optional::CACHE_URL is optional\n",
+		},
+		{
+			name: "escaping",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "invalid", "RATE", SeverityError, "RATE fails @range: 100% is\ntoo high\r"}, File: "config/a,b:c.env", Line: 2},
+			},
+			want: "::error file=config/a%2Cb%3Ac.env,line=2,title=Invalid env var::RATE fails @range: 100%25 is%0Atoo high%0D\n",
+		},
+		{
+			name: "finding order is kept",
+			findings: []LocatedFinding{
+				{Finding: Finding{SourceEnv, "missing", "B", SeverityError, ""}, File: ".env.example", Line: 2},
+				{Finding: Finding{SourceEnv, "missing", "A", SeverityError, ""}, File: ".env.example", Line: 1},
+			},
+			want: "::error file=.env.example,line=2,title=Missing env var::B is required by .env.example\n" +
+				"::error file=.env.example,line=1,title=Missing env var::A is required by .env.example\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateGitHubReport(tt.findings); got != tt.want {
+				t.Errorf("GenerateGitHubReport() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubReportFromComparison(t *testing.T) {
+	dir := t.TempDir()
+	env := writeFile(t, dir, ".env", "PORT=8080\nDEBUG=true\n")
+	example := writeFile(t, dir, ".env.example", "PORT=\n\n# Connection string\nDATABASE_URL=\n")
+
+	result, err := CompareEnvFiles(env, example, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing := "::error file=" + example + ",line=4,title=Missing env var::DATABASE_URL is required by " + example + "\n"
+	extra := "::warning file=" + env + ",line=2,title=Undocumented env var::DEBUG is present in .env but not documented in .env.example\n"
+
+	tests := []struct {
+		name   string
+		policy *ExitPolicy
+		want   string
+	}{
+		{"extra hidden", nil, missing},
+		{"extra shown", &ExitPolicy{ShowExtra: true}, missing + extra},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			located, err := LocateFindings(result.VisibleFindings(tt.policy), result.Locations, example, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := GenerateGitHubReport(located); got != tt.want {
+				t.Errorf("GenerateGitHubReport() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocateFindingsMergedFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, ".env", "PORT=8080\nDEBUG=true\nLOG_LEVEL=info\n")
+	local := writeFile(t, dir, ".env.local", "\nLOG_LEVEL=debug\nTRACE=1\n")
+	example := writeFile(t, dir, ".env.example", "PORT=\nDATABASE_URL=\n")
+
+	result, err := CompareMergedEnvFiles([]string{base, local}, example, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	located, err := LocateFindings(result.VisibleFindings(&ExitPolicy{ShowExtra: true}), result.Locations, example, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "::error file=" + example + ",line=2,title=Missing env var::DATABASE_URL is required by " + example + "\n" +
+		"::warning file=" + base + ",line=2,title=Undocumented env var::DEBUG is present in .env but not documented in .env.example\n" +
+		"::warning file=" + local + ",line=2,title=Undocumented env var::LOG_LEVEL is present in .env but not documented in .env.example\n" +
+		"::warning file=" + local + ",line=3,title=Undocumented env var::TRACE is present in .env but not documented in .env.example\n"
+	if got := GenerateGitHubReport(located); got != want {
+		t.Errorf("GenerateGitHubReport() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// Helm key styles for flattening values.yaml
+const (
+	HelmKeysEnv    = "env"    // database.hostName becomes DATABASE_HOST_NAME
+	HelmKeysDotted = "dotted" // database.hostName stays database.hostName
+)
+
+// envKeyInvalidChars matches runs of characters not allowed in env var names
+var envKeyInvalidChars = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// helmKeyJoiners build a flattened key from a values path
+var helmKeyJoiners = map[string]func(path []string) string{
+	HelmKeysEnv: func(path []string) string {
+		parts := make([]string, 0, len(path))
+		for _, part := range path {
+			parts = append(parts, envKeyInvalidChars.ReplaceAllString(camelToSnake(part), "_"))
+		}
+		return strings.Join(parts, "_")
+	},
+	HelmKeysDotted: func(path []string) string {
+		return strings.Join(path, ".")
+	},
+}
+
+// CompareHelmValues flattens Helm values files and compares their keys
+// against .env.example. Files are merged in order, later ones overriding
+// earlier ones as with helm -f.
+func CompareHelmValues(files []string, keyStyle, exampleFile string, opts *CompareOptions) (*DiffResult, error) {
+	joinKey, exists := helmKeyJoiners[keyStyle]
+	if !exists {
+		return nil, fmt.Errorf("unknown Helm key style %q (use %s or %s)", keyStyle, HelmKeysEnv, HelmKeysDotted)
+	}
+
+	env := make(parser.EnvVars)
+	for _, file := range files {
+		vars, err := parser.ParseHelmValues(file, joinKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range vars {
+			env[k] = v
+		}
+	}
+
+	return compareWithExampleFile(env, nil, exampleFile, opts)
+}
@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// ImageDiffResult splits the example's variables by whether an image bakes them in
+type ImageDiffResult struct {
+	Image     string
+	BakedIn   []string // Example variables the image sets with ENV
+	Runtime   []string // Example variables the image leaves to be supplied at runtime
+	ImageOnly []string // Variables the image sets that the example doesn't document (PATH, ...)
+}
+
+// CompareImageEnv compares the ENV entries of an image against .env.example
+func CompareImageEnv(image, exampleFile string, pull bool) (*ImageDiffResult, error) {
+	env, err := parser.ParseImageEnv(image, pull)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := loader.ParseEnvFile(exampleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImageDiffResult{
+		Image:     image,
+		BakedIn:   []string{},
+		Runtime:   []string{},
+		ImageOnly: []string{},
+	}
+
+	for key := range example {
+		if env.Has(key) {
+			result.BakedIn = append(result.BakedIn, key)
+		} else {
+			result.Runtime = append(result.Runtime, key)
+		}
+	}
+	for key := range env {
+		if !example.Has(key) {
+			result.ImageOnly = append(result.ImageOnly, key)
+		}
+	}
+
+	sort.Strings(result.BakedIn)
+	sort.Strings(result.Runtime)
+	sort.Strings(result.ImageOnly)
+
+	return result, nil
+}
+
+// GenerateImageReport creates a formatted report of what an image bakes in
+// versus what must be supplied at runtime
+func GenerateImageReport(result *ImageDiffResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if opts.Colorize {
+		report.WriteString(emoji(opts, "🐋") + fmt.Sprintf("Environment of image %s:\n\n", result.Image))
+	} else {
+		report.WriteString(fmt.Sprintf("Environment of image %s:\n\n", result.Image))
+	}
+
+	if len(result.Runtime) > 0 {
+		writeHeading(&report, opts, "🔌", "Must be supplied at runtime (not set in the image)", "Must be supplied at runtime")
+		writeKeyList(&report, result.Runtime, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	if len(result.BakedIn) > 0 {
+		writeHeading(&report, opts, "📦", "Baked into the image (ENV)", "Baked into the image")
+		writeKeyList(&report, result.BakedIn, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Image-only variables are mostly base image noise like PATH
+	if opts.Verbose && len(result.ImageOnly) > 0 {
+		writeHeading(&report, opts, "💡", "Set by the image but not in .env.example", "Set by the image only")
+		writeKeyList(&report, result.ImageOnly, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
@@ -0,0 +1,137 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InventorySources are the files an inventory is collected from. Empty paths are skipped.
+type InventorySources struct {
+	Env        string
+	Example    string
+	Compose    string
+	Dockerfile string
+}
+
+// Inventory is the union of variables across sources with a presence matrix
+type Inventory struct {
+	Sources   []string         `json:"sources"`   // Source files in column order
+	Variables []InventoryEntry `json:"variables"` // Sorted by key
+}
+
+// InventoryEntry is one variable and the sources that define or reference it
+type InventoryEntry struct {
+	Key     string   `json:"key"`
+	Sources []string `json:"sources"`
+}
+
+// In reports whether the variable appears in source
+func (e InventoryEntry) In(source string) bool {
+	for _, s := range e.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectInventory lists every variable found in the given sources
+func CollectInventory(sources InventorySources) (*Inventory, error) {
+	inventory := &Inventory{
+		Sources:   []string{},
+		Variables: []InventoryEntry{},
+	}
+	found := make(map[string][]string)
+
+	add := func(source string, keys []string) {
+		inventory.Sources = append(inventory.Sources, source)
+		for _, key := range keys {
+			found[key] = append(found[key], source)
+		}
+	}
+
+	for _, filename := range []string{sources.Env, sources.Example} {
+		if filename == "" {
+			continue
+		}
+		vars, err := loader.ParseEnvFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+		add(filename, vars.GetKeys())
+	}
+
+	if sources.Compose != "" {
+		composeInfo, err := loader.ParseComposeFile(sources.Compose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse compose file: %w", err)
+		}
+		add(sources.Compose, composeInfo.GetAllEnvVars())
+	}
+
+	if sources.Dockerfile != "" {
+		dockerfileInfo, err := loader.ParseDockerfile(sources.Dockerfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Dockerfile: %w", err)
+		}
+		add(sources.Dockerfile, dockerfileInfo.GetAllVars())
+	}
+
+	for key, in := range found {
+		inventory.Variables = append(inventory.Variables, InventoryEntry{Key: key, Sources: in})
+	}
+	sort.Slice(inventory.Variables, func(i, j int) bool {
+		return inventory.Variables[i].Key < inventory.Variables[j].Key
+	})
+
+	return inventory, nil
+}
+
+// GenerateInventoryReport renders the inventory as a presence table
+func GenerateInventoryReport(inventory *Inventory, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if len(inventory.Variables) == 0 {
+		report.WriteString("No variables found.\n")
+		return report.String()
+	}
+
+	present, absent := "✓", "·"
+	if !opts.Emoji {
+		present, absent = "x", "-"
+	}
+
+	headers := append([]string{"VARIABLE"}, inventory.Sources...)
+	rows := make([][]string, 0, len(inventory.Variables))
+	for _, entry := range inventory.Variables {
+		row := []string{entry.Key}
+		for _, source := range inventory.Sources {
+			if entry.In(source) {
+				row = append(row, present)
+			} else {
+				row = append(row, absent)
+			}
+		}
+		rows = append(rows, row)
+	}
+	report.WriteString(renderTable(headers, rows))
+
+	report.WriteString(fmt.Sprintf("\n%d variables across %d sources\n", len(inventory.Variables), len(inventory.Sources)))
+
+	return report.String()
+}
+
+// GenerateInventoryJSON renders the inventory as indented JSON
+func GenerateInventoryJSON(inventory *Inventory) (string, error) {
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,170 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// JSONReport is the structured form of an env comparison
+type JSONReport struct {
+	Missing      []string           `json:"missing"`
+	Extra        []string           `json:"extra"`
+	Optional     []string           `json:"optional,omitempty"`
+	FromOS       []string           `json:"from_os,omitempty"`
+	Deprecated   []JSONDeprecation  `json:"deprecated,omitempty"`
+	Invalid      []JSONInvalid      `json:"invalid,omitempty"`
+	Empty        []string           `json:"required_empty,omitempty"`
+	EmptyValues  []string           `json:"empty_values,omitempty"`
+	Changed      []JSONValueChange  `json:"changed,omitempty"`
+	Unresolved   []JSONUnresolved   `json:"unresolved,omitempty"`
+	Duplicates   []JSONDuplicate    `json:"duplicates,omitempty"`
+	CaseMismatch []JSONCaseMismatch `json:"case_mismatches,omitempty"`
+	Secrets      []JSONSecret       `json:"possible_secrets,omitempty"`
+	Sources      map[string]string  `json:"sources,omitempty"`
+	ExampleTotal int                `json:"example_total"`
+	Coverage     float64            `json:"coverage"`
+	HasIssues    bool               `json:"has_issues"`
+	ExitCode     int                `json:"exit_code"`
+	ExitReason   ExitReason         `json:"exit_reason"`
+}
+
+// JSONDeprecation is a deprecated key in structured output
+type JSONDeprecation struct {
+	Key     string `json:"key"`
+	Message string `json:"message,omitempty"`
+}
+
+// JSONSecret is a likely secret in structured output; its value is never included
+type JSONSecret struct {
+	Key     string   `json:"key"`
+	Reasons []string `json:"reasons"`
+}
+
+// JSONDuplicate is a key assigned more than once in structured output
+type JSONDuplicate struct {
+	Key   string `json:"key"`
+	File  string `json:"file,omitempty"`
+	Lines []int  `json:"lines"`
+}
+
+// JSONCaseMismatch is an env key spelled with a different case than the
+// example in structured output
+type JSONCaseMismatch struct {
+	Key    string `json:"key"`
+	EnvKey string `json:"env_key"`
+}
+
+// JSONUnresolved is a key with unresolved references in structured output
+type JSONUnresolved struct {
+	Key  string   `json:"key"`
+	Refs []string `json:"refs"`
+}
+
+// JSONInvalid is a value failing validation in structured output
+type JSONInvalid struct {
+	Key        string `json:"key"`
+	Annotation string `json:"annotation"`
+	Message    string `json:"message"`
+}
+
+// JSONValueChange is a differing value in structured output
+type JSONValueChange struct {
+	Key      string `json:"key"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// GenerateJSONReport renders a diff result and its exit decision as indented JSON
+func GenerateJSONReport(result *DiffResult, status ExitStatus) (string, error) {
+	report := JSONReport{
+		Missing:      result.Missing,
+		Extra:        result.Extra,
+		Optional:     result.Optional,
+		FromOS:       result.FromOS,
+		Deprecated:   make([]JSONDeprecation, 0, len(result.Deprecated)),
+		Invalid:      make([]JSONInvalid, 0, len(result.Invalid)),
+		Empty:        result.Empty,
+		EmptyValues:  result.EmptyValues,
+		Sources:      result.Origins,
+		ExampleTotal: result.ExampleTotal,
+		Coverage:     math.Round(result.Coverage()*10) / 10,
+		HasIssues:    result.HasIssues(),
+		ExitCode:     status.Code,
+		ExitReason:   status.Reason,
+	}
+
+	for _, d := range result.Deprecated {
+		report.Deprecated = append(report.Deprecated, JSONDeprecation{Key: d.Key, Message: d.Message})
+	}
+	for _, c := range result.Changed {
+		report.Changed = append(report.Changed, JSONValueChange{Key: c.Key, Expected: c.Expected, Actual: c.Actual})
+	}
+	for _, secret := range result.Secrets {
+		report.Secrets = append(report.Secrets, JSONSecret{Key: secret.Key, Reasons: secret.Reasons})
+	}
+	for _, m := range result.CaseMismatch {
+		report.CaseMismatch = append(report.CaseMismatch, JSONCaseMismatch{Key: m.Key, EnvKey: m.EnvKey})
+	}
+	for _, d := range result.Duplicates {
+		report.Duplicates = append(report.Duplicates, JSONDuplicate{Key: d.Key, File: d.File, Lines: d.Lines})
+	}
+	for _, u := range result.Unresolved {
+		report.Unresolved = append(report.Unresolved, JSONUnresolved{Key: u.Key, Refs: u.Refs})
+	}
+	for _, inv := range result.Invalid {
+		report.Invalid = append(report.Invalid, JSONInvalid{Key: inv.Key, Annotation: inv.Annotation, Message: inv.Message})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
+
+// JSONFindingsReport is the structured form of a check that reports
+// normalized findings, such as a reference scan
+type JSONFindingsReport struct {
+	Findings   []JSONFinding `json:"findings"`
+	HasIssues  bool          `json:"has_issues"`
+	ExitCode   int           `json:"exit_code"`
+	ExitReason ExitReason    `json:"exit_reason"`
+}
+
+// JSONFinding is a normalized finding in structured output
+type JSONFinding struct {
+	Source   string `json:"source"`
+	Category string `json:"category"`
+	Key      string `json:"key"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// GenerateFindingsJSON renders findings and their exit decision as indented JSON
+func GenerateFindingsJSON(findings []Finding, status ExitStatus) (string, error) {
+	report := JSONFindingsReport{
+		Findings:   make([]JSONFinding, 0, len(findings)),
+		HasIssues:  !status.OK(),
+		ExitCode:   status.Code,
+		ExitReason: status.Reason,
+	}
+
+	for _, f := range findings {
+		report.Findings = append(report.Findings, JSONFinding{
+			Source:   f.Source,
+			Category: f.Category,
+			Key:      f.Key,
+			Severity: f.Severity.String(),
+			Message:  f.Message,
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
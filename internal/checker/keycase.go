@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// CaseMismatch is an env key that matches an example key only when case is
+// ignored, e.g. Database_Url for DATABASE_URL
+type CaseMismatch struct {
+	Key    string // Spelling in the example
+	EnvKey string // Spelling in env
+}
+
+// FoldKeyCase renames the env keys that equal an example key under case
+// folding to the example's spelling, so they compare as the same variable,
+// and returns the renamed keys. A key whose exact spelling is already in env
+// keeps its other spellings as they are; of several other spellings the
+// alphabetically first one is renamed.
+func FoldKeyCase(env, example parser.EnvVars) (parser.EnvVars, []CaseMismatch) {
+	exampleKeys := make(map[string]string, len(example))
+	for key := range example {
+		exampleKeys[strings.ToUpper(key)] = key
+	}
+
+	envKeys := make([]string, 0, len(env))
+	for key := range env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+
+	folded := make(parser.EnvVars, len(env))
+	mismatches := []CaseMismatch{}
+	for _, key := range envKeys {
+		target, ok := exampleKeys[strings.ToUpper(key)]
+		if !ok || target == key || env.Has(target) || folded.Has(target) {
+			folded[key] = env[key]
+			continue
+		}
+		folded[target] = env[key]
+		mismatches = append(mismatches, CaseMismatch{Key: target, EnvKey: key})
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Key < mismatches[j].Key
+	})
+	return folded, mismatches
+}
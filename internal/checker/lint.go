@@ -0,0 +1,275 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// Severity ranks how serious a finding is
+type Severity int
+
+// Severity levels, from least to most serious
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// LintFinding is a single lint rule violation
+type LintFinding struct {
+	Rule     string
+	Severity Severity
+	Key      string
+	Line     int
+	Message  string
+}
+
+// LintResult holds the lint findings for one env file
+type LintResult struct {
+	File     string
+	Findings []LintFinding
+}
+
+// HasIssues returns true if there are any findings
+func (l *LintResult) HasIssues() bool {
+	return len(l.Findings) > 0
+}
+
+// HasSeverity returns true if any finding is at least as serious as min
+func (l *LintResult) HasSeverity(min Severity) bool {
+	for _, finding := range l.Findings {
+		if finding.Severity >= min {
+			return true
+		}
+	}
+	return false
+}
+
+// lintRule inspects a parsed env file and returns its findings
+type lintRule func(parsed *parser.ParsedFile) []LintFinding
+
+// lintRules are run in order by LintParsedFile
+var lintRules = []lintRule{
+	lintInconsistentQuoting,
+	lintNamingConvention,
+	lintNumericPitfalls,
+}
+
+// LintOptions configures the lint rules
+type LintOptions struct {
+	// Comment markers reported as open action items, e.g. TODO; empty
+	// disables the todo-marker rule
+	TodoMarkers []string
+}
+
+// DefaultLintOptions returns the default lint configuration
+func DefaultLintOptions() *LintOptions {
+	return &LintOptions{TodoMarkers: DefaultTodoMarkers}
+}
+
+// LintEnvFile parses and lints an env file
+func LintEnvFile(filename string, opts *LintOptions) (*LintResult, error) {
+	parsed, err := parser.ParseEnvFileOrdered(filename)
+	if err != nil {
+		return nil, err
+	}
+	return LintParsedFile(parsed, opts), nil
+}
+
+// LintParsedFile runs every lint rule over a parsed env file
+func LintParsedFile(parsed *parser.ParsedFile, opts *LintOptions) *LintResult {
+	if opts == nil {
+		opts = DefaultLintOptions()
+	}
+
+	result := &LintResult{
+		File:     parsed.Filename,
+		Findings: []LintFinding{},
+	}
+
+	rules := append(lintRules[:len(lintRules):len(lintRules)], lintTodoMarkers(opts.TodoMarkers))
+	for _, rule := range rules {
+		result.Findings = append(result.Findings, rule(parsed)...)
+	}
+
+	// Report findings in file order
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		return result.Findings[i].Line < result.Findings[j].Line
+	})
+
+	return result
+}
+
+// quoteStyleName describes how a value was quoted
+func quoteStyleName(quote byte) string {
+	switch quote {
+	case '"':
+		return "double-quoted"
+	case '\'':
+		return "single-quoted"
+	default:
+		return "unquoted"
+	}
+}
+
+// valueNeedsQuotes reports whether a value contains characters that are only
+// safe inside quotes
+func valueNeedsQuotes(value string) bool {
+	return strings.ContainsAny(value, " \t\n\r#\"'`$\\")
+}
+
+// lintInconsistentQuoting flags values quoted differently from similar values
+// in the same file. Values that need quoting and values that don't are
+// compared separately, and the most common style in each group wins.
+func lintInconsistentQuoting(parsed *parser.ParsedFile) []LintFinding {
+	findings := []LintFinding{}
+
+	groups := map[bool][]parser.EnvEntry{}
+	for _, entry := range parsed.Entries {
+		if entry.Value == "" {
+			continue
+		}
+		needsQuotes := valueNeedsQuotes(entry.Value)
+		groups[needsQuotes] = append(groups[needsQuotes], entry)
+	}
+
+	for _, needsQuotes := range []bool{true, false} {
+		entries := groups[needsQuotes]
+
+		counts := make(map[byte]int)
+		for _, entry := range entries {
+			counts[entry.Quote]++
+		}
+		if len(counts) < 2 {
+			continue
+		}
+
+		// Ties favour double quotes for special values and no quotes otherwise
+		dominant := byte(0)
+		if needsQuotes {
+			dominant = '"'
+		}
+		for _, quote := range []byte{'"', '\'', 0} {
+			if counts[quote] > counts[dominant] {
+				dominant = quote
+			}
+		}
+
+		kind := "simple"
+		if needsQuotes {
+			kind = "special-character"
+		}
+
+		for _, entry := range entries {
+			if entry.Quote == dominant {
+				continue
+			}
+			findings = append(findings, LintFinding{
+				Rule:     "inconsistent-quoting",
+				Severity: SeverityLow,
+				Key:      entry.Key,
+				Line:     entry.Line,
+				Message: fmt.Sprintf("value is %s but %d other %s values are %s; use %s consistently",
+					quoteStyleName(entry.Quote), counts[dominant], kind, quoteStyleName(dominant), quoteStyleName(dominant)),
+			})
+		}
+	}
+
+	return findings
+}
+
+// GenerateLintReport creates a formatted report for lint findings
+func GenerateLintReport(result *LintResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("Lint passed: no findings in %s.\n", result.File))
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + fmt.Sprintf("%s looks tidy.\n", result.File))
+		if opts.ShowDuck {
+			report.WriteString("(Your gopher-duck admires your formatting.)\n")
+		}
+		return report.String()
+	}
+
+	// Header with duck
+	if opts.Plain {
+		report.WriteString(fmt.Sprintf("Lint found %d issues in %s\n\n", len(result.Findings), result.File))
+	} else if opts.ShowDuck {
+		// Only findings that fail the run make the duck angry
+		if result.HasSeverity(SeverityWarning) {
+			report.WriteString(quack.GetAngryDuck() + "\n")
+			report.WriteString("QUACK! " + emoji(opts, "🦆") + "Lint findings detected:\n\n")
+		} else {
+			report.WriteString(quack.GetContentDuck() + "\n")
+			report.WriteString("Quack. " + emoji(opts, "🦆") + "Only minor lint findings:\n\n")
+		}
+	}
+
+	// Informational findings are only counted unless verbose
+	listed := result.Findings
+	if !opts.Verbose {
+		listed = []LintFinding{}
+		for _, finding := range result.Findings {
+			if finding.Severity > SeverityInfo {
+				listed = append(listed, finding)
+			}
+		}
+	}
+
+	if len(listed) > 0 {
+		if opts.Colorize {
+			report.WriteString(emoji(opts, "🧹") + fmt.Sprintf("Lint findings in %s:\n", result.File))
+		} else {
+			report.WriteString(fmt.Sprintf("Lint findings in %s:\n", result.File))
+		}
+
+		lines := make([]string, 0, len(listed))
+		for _, finding := range listed {
+			if finding.Key == "" {
+				lines = append(lines, fmt.Sprintf("[%s] line %d: %s (%s)",
+					finding.Severity, finding.Line, finding.Message, finding.Rule))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("[%s] line %d %s: %s (%s)",
+				finding.Severity, finding.Line, finding.Key, finding.Message, finding.Rule))
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	if hidden := len(result.Findings) - len(listed); hidden > 0 {
+		noun := "findings"
+		if hidden == 1 {
+			noun = "finding"
+		}
+		report.WriteString(fmt.Sprintf("%d informational %s, e.g. TODO comments (use --verbose to list them)\n", hidden, noun))
+	}
+
+	return report.String()
+}
@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+func TestValueNeedsQuotes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"8080", false},
+		{"postgres://localhost/db", false},
+		{"hello world", true},
+		{"a#b", true},
+		{"$HOME", true},
+		{"line1\nline2", true},
+		{"line1\r\nline2", true},
+	}
+
+	for _, tt := range tests {
+		if got := valueNeedsQuotes(tt.value); got != tt.want {
+			t.Errorf("valueNeedsQuotes(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLintQuotedMultilineValueIsConsistent(t *testing.T) {
+	content := "A=1\nB=2\nC=3\nMULTI=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\n"
+	parsed, err := parser.ParseEnvReader(strings.NewReader(content), ".env", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, finding := range LintParsedFile(parsed, nil).Findings {
+		if finding.Rule == "inconsistent-quoting" {
+			t.Errorf("unexpected finding for %s: %s", finding.Key, finding.Message)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package checker
+
+import "github.com/DuckDHD/EnvQuack/internal/parser"
+
+// loader parses compose, Dockerfile and env files for the checkers. It is nil
+// by default, which parses on every call.
+var loader *parser.Cache
+
+// UseParseCache makes the checkers reuse parse results from cache for the rest
+// of the invocation. Pass nil to disable caching.
+func UseParseCache(cache *parser.Cache) {
+	loader = cache
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// BenchmarkAuditParseCache runs the comparisons of an audit, which read the
+// same env, compose and Dockerfile several times, with and without a cache
+func BenchmarkAuditParseCache(b *testing.B) {
+	dir := b.TempDir()
+
+	var env, example, compose, dockerfile strings.Builder
+	compose.WriteString("services:\n")
+	for s := 0; s < 20; s++ {
+		fmt.Fprintf(&compose, "  svc%d:\n    image: app\n    environment:\n", s)
+		for k := 0; k < 50; k++ {
+			fmt.Fprintf(&compose, "      - VAR_%d_%d=${VAR_%d_%d:-default}\n", s, k, s, k)
+		}
+	}
+	for k := 0; k < 1000; k++ {
+		fmt.Fprintf(&env, "VAR_%d_%d=value%d\n", k/50, k%50, k)
+		fmt.Fprintf(&example, "# Variable %d\nVAR_%d_%d=\n", k, k/50, k%50)
+		fmt.Fprintf(&dockerfile, "ENV VAR_%d_%d=value\n", k/50, k%50)
+	}
+
+	envFile := writeFile(b, dir, ".env", env.String())
+	exampleFile := writeFile(b, dir, ".env.example", example.String())
+	composeFile := writeFile(b, dir, "docker-compose.yml", compose.String())
+	dockerfileFile := writeFile(b, dir, "Dockerfile", "FROM scratch\n"+dockerfile.String())
+
+	audit := func(b *testing.B) {
+		for i := 0; i < 3; i++ {
+			if _, err := CompareEnvFiles(envFile, exampleFile, nil); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := CompareComposeWithEnv(composeFile, []string{envFile}, nil); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := CompareDockerfileWithEnv(dockerfileFile, []string{envFile}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		UseParseCache(nil)
+		for i := 0; i < b.N; i++ {
+			audit(b)
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		defer UseParseCache(nil)
+		for i := 0; i < b.N; i++ {
+			UseParseCache(parser.NewCache())
+			audit(b)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// MakefileDiffResult represents comparison between a Makefile and the example
+type MakefileDiffResult struct {
+	Undocumented []string // Variables the Makefile expects from the environment but missing in the example
+	WithDefault  []string // ?= variables missing in the example (the Makefile has a fallback)
+}
+
+// HasIssues returns true if the Makefile relies on undocumented variables
+func (m *MakefileDiffResult) HasIssues() bool {
+	return len(m.Undocumented) > 0
+}
+
+// CompareMakefileWithExample checks the env a Makefile relies on against the example file
+func CompareMakefileWithExample(makefile, exampleFile string) (*MakefileDiffResult, error) {
+	info, err := parser.ParseMakefile(makefile)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := loader.ParseEnvFile(exampleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MakefileDiffResult{
+		Undocumented: []string{},
+		WithDefault:  []string{},
+	}
+
+	// Required is already sorted
+	for _, key := range info.Required {
+		if !example.Has(key) {
+			result.Undocumented = append(result.Undocumented, key)
+		}
+	}
+
+	for key := range info.Defaults {
+		if !example.Has(key) {
+			result.WithDefault = append(result.WithDefault, key)
+		}
+	}
+	sort.Strings(result.WithDefault)
+
+	return result, nil
+}
+
+// GenerateMakefileReport creates a formatted report for a Makefile check
+func GenerateMakefileReport(result *MakefileDiffResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString("Makefile check passed: all referenced variables are documented.\n")
+		} else {
+			report.WriteString(emoji(opts, "✅") + "All variables used by the Makefile are documented.\n")
+		}
+	} else {
+		// Header with duck
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("Makefile check failed: %d undocumented\n\n", len(result.Undocumented)))
+		} else if opts.ShowDuck {
+			report.WriteString(quack.GetDuckForSeverity(len(result.Undocumented), 0) + "\n")
+			report.WriteString("QUACK! " + emoji(opts, "🦆") + "The Makefile needs undocumented variables:\n\n")
+		}
+
+		writeHeading(&report, opts, "🔴", "Variables referenced in the Makefile but missing in .env.example", "Undocumented variables")
+		writeKeyList(&report, result.Undocumented, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// ?= variables work without the environment, so they are informational
+	if opts.Verbose && len(result.WithDefault) > 0 {
+		writeHeading(&report, opts, "💡", "Variables with a ?= default in the Makefile (not in .env.example)", "Makefile variables with a default")
+		writeKeyList(&report, result.WithDefault, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
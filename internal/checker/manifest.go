@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// AnnotationManifest is the schema documented in the comments of an example
+// file: every key with its annotations and doc comment
+type AnnotationManifest struct {
+	File      string          `json:"file"`
+	Variables []ManifestEntry `json:"variables"` // In declaration order
+}
+
+// ManifestEntry is one documented key of an example file
+type ManifestEntry struct {
+	Key         string            `json:"key"`
+	Line        int               `json:"line"`
+	Default     string            `json:"default"`            // The example value
+	Optional    bool              `json:"optional,omitempty"` // Commented out, like `# KEY=`
+	Doc         []string          `json:"doc"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// CollectAnnotations reads the annotations and doc comments of every key in
+// an example file, commented-out optional keys included
+func CollectAnnotations(exampleFile string, opts *parser.ParseOptions) (*AnnotationManifest, error) {
+	exampleParse := parser.DefaultParseOptions()
+	if opts != nil {
+		*exampleParse = *opts
+	}
+	exampleParse.Docker = false
+	exampleParse.CommentedKeys = true
+
+	parsed, err := loader.ParseEnvFileWithOptions(exampleFile, exampleParse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", exampleFile, err)
+	}
+
+	manifest := &AnnotationManifest{File: exampleFile, Variables: []ManifestEntry{}}
+	add := func(entry parser.EnvEntry, optional bool) {
+		doc := entry.Doc
+		if doc == nil {
+			doc = []string{}
+		}
+		annotations := map[string]string(entry.Annotations)
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		manifest.Variables = append(manifest.Variables, ManifestEntry{
+			Key:         entry.Key,
+			Line:        entry.Line,
+			Default:     entry.Value,
+			Optional:    optional,
+			Doc:         doc,
+			Annotations: annotations,
+		})
+	}
+
+	for _, entry := range parsed.Entries {
+		add(entry, false)
+	}
+	for _, entry := range parsed.Commented {
+		add(entry, true)
+	}
+
+	sort.SliceStable(manifest.Variables, func(i, j int) bool {
+		return manifest.Variables[i].Line < manifest.Variables[j].Line
+	})
+
+	return manifest, nil
+}
+
+// GenerateAnnotationsReport lists each key with its annotations and doc comment
+func GenerateAnnotationsReport(manifest *AnnotationManifest) string {
+	var report strings.Builder
+
+	if len(manifest.Variables) == 0 {
+		report.WriteString(fmt.Sprintf("No variables found in %s.\n", manifest.File))
+		return report.String()
+	}
+
+	for _, entry := range manifest.Variables {
+		header := fmt.Sprintf("%s (line %d)", entry.Key, entry.Line)
+		if entry.Optional {
+			header += ", optional"
+		}
+		report.WriteString(header + "\n")
+
+		names := make([]string, 0, len(entry.Annotations))
+		for name := range entry.Annotations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			report.WriteString(strings.TrimRight(fmt.Sprintf("  @%s %s", name, entry.Annotations[name]), " ") + "\n")
+		}
+		for _, line := range entry.Doc {
+			report.WriteString("  # " + line + "\n")
+		}
+	}
+
+	return report.String()
+}
+
+// GenerateAnnotationsJSON renders the manifest as indented JSON
+func GenerateAnnotationsJSON(manifest *AnnotationManifest) (string, error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode annotations: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// integerValueRegex matches decimal integers with an optional sign
+var integerValueRegex = regexp.MustCompile(`^[+-]?[0-9]+$`)
+
+// numericPitfall describes how an integer-looking value may be misread by
+// consumers, or returns "" when the value is safe or not an integer
+func numericPitfall(value string) string {
+	if !integerValueRegex.MatchString(value) {
+		return ""
+	}
+
+	digits := strings.TrimLeft(value, "+-")
+	if len(digits) > 1 && digits[0] == '0' {
+		return "has a leading zero, so some parsers read it as octal or drop the zero"
+	}
+
+	if _, err := strconv.ParseInt(value, 10, 64); errors.Is(err, strconv.ErrRange) {
+		return "exceeds the int64 range and will overflow or lose precision"
+	}
+
+	return ""
+}
+
+// validateType checks a value against a @type annotation such as "@type int",
+// "@type bool" or "@type url". Unknown types, and string, are not checked.
+func validateType(value, arg string) error {
+	switch strings.TrimSpace(arg) {
+	case "int":
+		if !integerValueRegex.MatchString(value) {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		if risk := numericPitfall(value); risk != "" {
+			return fmt.Errorf("%q %s", value, risk)
+		}
+	case "bool":
+		if _, ok := normalizeBool(value); !ok {
+			return fmt.Errorf("%q is not a boolean (true/false, 1/0, yes/no, on/off)", value)
+		}
+	case "url":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%q is not a URL with a scheme and host", value)
+		}
+	}
+	return nil
+}
+
+// lintNumericPitfalls flags integer-looking values that consumers commonly
+// misread, e.g. PORT=08080 parsed as octal or IDs beyond int64. Values of keys
+// annotated with a @type other than int are skipped.
+func lintNumericPitfalls(parsed *parser.ParsedFile) []LintFinding {
+	findings := []LintFinding{}
+
+	for _, entry := range parsed.Entries {
+		if typ, ok := entry.Annotations["type"]; ok && strings.TrimSpace(typ) != "int" {
+			continue
+		}
+
+		value := strings.TrimSpace(entry.Value)
+		if risk := numericPitfall(value); risk != "" {
+			findings = append(findings, LintFinding{
+				Rule:     "numeric-pitfall",
+				Severity: SeverityWarning,
+				Key:      entry.Key,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("value %q %s", value, risk),
+			})
+		}
+	}
+
+	return findings
+}
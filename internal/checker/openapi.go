@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// OpenAPIDiffResult represents comparison between OpenAPI server variables and the example
+type OpenAPIDiffResult struct {
+	Undocumented []string // Server variables without a default that are missing in the example
+	WithDefault  []string // Server variables with a default that are missing in the example
+}
+
+// HasIssues returns true if server templates need undocumented variables
+func (o *OpenAPIDiffResult) HasIssues() bool {
+	return len(o.Undocumented) > 0
+}
+
+// CompareOpenAPIWithExample checks the server variables of an OpenAPI document
+// against the example file. Variable names are matched as env keys, so
+// basePath is expected as BASE_PATH.
+func CompareOpenAPIWithExample(specFile, exampleFile string) (*OpenAPIDiffResult, error) {
+	info, err := parser.ParseOpenAPIServers(specFile)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := loader.ParseEnvFile(exampleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OpenAPIDiffResult{
+		Undocumented: []string{},
+		WithDefault:  []string{},
+	}
+
+	for _, name := range info.Required {
+		if key := camelToSnake(name); !example.Has(key) {
+			result.Undocumented = append(result.Undocumented, key)
+		}
+	}
+
+	for name := range info.Defaults {
+		if key := camelToSnake(name); !example.Has(key) {
+			result.WithDefault = append(result.WithDefault, key)
+		}
+	}
+
+	sort.Strings(result.Undocumented)
+	sort.Strings(result.WithDefault)
+
+	return result, nil
+}
+
+// GenerateOpenAPIReport creates a formatted report for an OpenAPI server variables check
+func GenerateOpenAPIReport(result *OpenAPIDiffResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString("OpenAPI check passed: every server variable without a default is documented.\n")
+		} else {
+			report.WriteString(emoji(opts, "✅") + "Every OpenAPI server variable without a default is documented.\n")
+		}
+	} else {
+		// Header with duck
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("OpenAPI check failed: %d undocumented\n\n", len(result.Undocumented)))
+		} else if opts.ShowDuck {
+			report.WriteString(quack.GetAngryDuck() + "\n")
+			report.WriteString("QUACK! " + emoji(opts, "🦆") + "OpenAPI servers need undocumented variables:\n\n")
+		}
+
+		writeHeading(&report, opts, "🔴", "Server variables without a default missing in .env.example", "Undocumented server variables")
+		writeKeyList(&report, result.Undocumented, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Variables with a default still work when unset
+	if opts.Verbose && len(result.WithDefault) > 0 {
+		writeHeading(&report, opts, "💡", "Server variables falling back to their default (not in .env.example)", "Server variables using their default")
+		writeKeyList(&report, result.WithDefault, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
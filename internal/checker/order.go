@@ -0,0 +1,56 @@
+package checker
+
+import "sort"
+
+// OrderByDeclaration reorders the findings of a comparison from alphabetical
+// to declaration order: keys from the example follow exampleKeys, extra keys
+// follow envKeys. Keys without a position keep their alphabetical order after
+// the rest.
+func OrderByDeclaration(result *DiffResult, exampleKeys, envKeys []string) {
+	examplePos := declarationIndex(exampleKeys)
+	envPos := declarationIndex(envKeys)
+
+	sortKeys(result.Missing, examplePos)
+	sortKeys(result.Empty, examplePos)
+	sortKeys(result.EmptyValues, examplePos)
+	sortKeys(result.FromOS, examplePos)
+	sortKeys(result.Extra, envPos)
+	sortKeys(result.Optional, envPos)
+
+	sort.SliceStable(result.Invalid, func(i, j int) bool {
+		return position(examplePos, result.Invalid[i].Key) < position(examplePos, result.Invalid[j].Key)
+	})
+	sort.SliceStable(result.Deprecated, func(i, j int) bool {
+		return position(examplePos, result.Deprecated[i].Key) < position(examplePos, result.Deprecated[j].Key)
+	})
+	sort.SliceStable(result.Changed, func(i, j int) bool {
+		return position(examplePos, result.Changed[i].Key) < position(examplePos, result.Changed[j].Key)
+	})
+	sort.SliceStable(result.Secrets, func(i, j int) bool {
+		return position(envPos, result.Secrets[i].Key) < position(envPos, result.Secrets[j].Key)
+	})
+}
+
+// declarationIndex maps each key to its position
+func declarationIndex(keys []string) map[string]int {
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+	return index
+}
+
+// position returns the declaration position of key, after every known key if it has none
+func position(index map[string]int, key string) int {
+	if pos, ok := index[key]; ok {
+		return pos
+	}
+	return len(index)
+}
+
+// sortKeys stable-sorts keys by declaration position
+func sortKeys(keys []string, index map[string]int) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return position(index, keys[i]) < position(index, keys[j])
+	})
+}
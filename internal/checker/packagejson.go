@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// PackageScriptsDiffResult represents comparison between package.json scripts and the example
+type PackageScriptsDiffResult struct {
+	Undocumented []string // Variables scripts expect from the environment but missing in the example
+	InlineOnly   []string // Variables assigned inline in scripts but missing in the example
+}
+
+// HasIssues returns true if scripts rely on undocumented variables
+func (p *PackageScriptsDiffResult) HasIssues() bool {
+	return len(p.Undocumented) > 0
+}
+
+// ComparePackageScriptsWithExample checks env used by package.json scripts against the example file
+func ComparePackageScriptsWithExample(packageFile, exampleFile string) (*PackageScriptsDiffResult, error) {
+	info, err := parser.ParsePackageJSON(packageFile)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := loader.ParseEnvFile(exampleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PackageScriptsDiffResult{
+		Undocumented: []string{},
+		InlineOnly:   []string{},
+	}
+
+	// Required is already sorted
+	for _, key := range info.Required {
+		if !example.Has(key) {
+			result.Undocumented = append(result.Undocumented, key)
+		}
+	}
+
+	for key := range info.Defined {
+		if !example.Has(key) {
+			result.InlineOnly = append(result.InlineOnly, key)
+		}
+	}
+	sort.Strings(result.InlineOnly)
+
+	return result, nil
+}
+
+// GeneratePackageScriptsReport creates a formatted report for a package.json scripts check
+func GeneratePackageScriptsReport(result *PackageScriptsDiffResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString("Package scripts check passed: all referenced variables are documented.\n")
+		} else {
+			report.WriteString(emoji(opts, "✅") + "All variables used by package.json scripts are documented.\n")
+		}
+	} else {
+		// Header with duck
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("Package scripts check failed: %d undocumented\n\n", len(result.Undocumented)))
+		} else if opts.ShowDuck {
+			report.WriteString(quack.GetAngryDuck() + "\n")
+			report.WriteString("QUACK! " + emoji(opts, "🦆") + "package.json scripts need undocumented variables:\n\n")
+		}
+
+		writeHeading(&report, opts, "🔴", "Variables referenced in scripts but missing in .env.example", "Undocumented variables")
+		writeKeyList(&report, result.Undocumented, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Inline assignments are informational, they carry their own value
+	if opts.Verbose && len(result.InlineOnly) > 0 {
+		writeHeading(&report, opts, "💡", "Variables set inline in scripts (not in .env.example)", "Inline script variables")
+		writeKeyList(&report, result.InlineOnly, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// CompareEnvFilesParallel compares each env file against the example with at
+// most workers comparisons running at once (0 for GOMAXPROCS). The roll-up
+// lists the files in the order given, however the comparisons finish, and
+// the error of the first failing file in that order is returned.
+func CompareEnvFilesParallel(files []string, exampleFile string, opts *CompareOptions, workers int) (*Rollup, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(files))
+
+	results := make([]*DiffResult, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each job writes only its own slot, so no locking is needed
+			for i := range jobs {
+				results[i], errs[i] = CompareEnvFiles(files[i], exampleFile, opts)
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	rollup := &Rollup{}
+	for i, file := range files {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to compare %s: %w", file, errs[i])
+		}
+		rollup.Add(file, results[i])
+	}
+
+	return rollup, nil
+}
@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// writeEnvTree writes an example of 50 keys and n env files, each missing a
+// different key, and returns the example and env paths
+func writeEnvTree(tb testing.TB, n int) (string, []string) {
+	tb.Helper()
+	dir := tb.TempDir()
+
+	var example strings.Builder
+	for k := 0; k < 50; k++ {
+		fmt.Fprintf(&example, "# Key %d\nKEY_%d=\n", k, k)
+	}
+	exampleFile := writeFile(tb, dir, ".env.example", example.String())
+
+	files := make([]string, n)
+	for i := range files {
+		var env strings.Builder
+		for k := 0; k < 50; k++ {
+			if k != i%50 {
+				fmt.Fprintf(&env, "KEY_%d=value%d\n", k, i)
+			}
+		}
+		files[i] = writeFile(tb, dir, fmt.Sprintf("service%03d.env", i), env.String())
+	}
+	return exampleFile, files
+}
+
+func TestCompareEnvFilesParallelKeepsOrder(t *testing.T) {
+	example, files := writeEnvTree(t, 20)
+
+	serial, err := CompareEnvFilesParallel(files, example, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{0, 2, 8, 64} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			rollup, err := CompareEnvFilesParallel(files, example, nil, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rollup, serial) {
+				t.Error("parallel roll-up differs from the serial one")
+			}
+			for i, f := range rollup.Files {
+				if f.File != files[i] {
+					t.Errorf("file %d = %s, want %s", i, f.File, files[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareEnvFilesParallelReportsFirstError(t *testing.T) {
+	example, files := writeEnvTree(t, 4)
+	dir := filepath.Dir(example)
+	files = append(files[:1], append([]string{filepath.Join(dir, "first.env"), filepath.Join(dir, "second.env")}, files[1:]...)...)
+
+	_, err := CompareEnvFilesParallel(files, example, nil, 4)
+	if err == nil || !strings.Contains(err.Error(), "first.env") {
+		t.Errorf("err = %v, want the error of first.env", err)
+	}
+}
+
+func BenchmarkCompareEnvFilesParallel(b *testing.B) {
+	example, files := writeEnvTree(b, 100)
+
+	for _, workers := range []int{1, 0} {
+		name := fmt.Sprintf("%d workers", workers)
+		if workers == 0 {
+			name = "GOMAXPROCS workers"
+		}
+		b.Run(name, func(b *testing.B) {
+			UseParseCache(parser.NewCache())
+			defer UseParseCache(nil)
+			for i := 0; i < b.N; i++ {
+				if _, err := CompareEnvFilesParallel(files, example, nil, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
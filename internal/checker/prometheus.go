@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prometheusMetric is one gauge in the textfile exposition format
+type prometheusMetric struct {
+	name  string
+	help  string
+	value int
+}
+
+// GeneratePrometheusReport renders the summary counts of an env comparison in
+// the Prometheus text exposition format, for node_exporter's textfile
+// collector. All metrics are gauges since each run replaces the last, so
+// none of them use the _total suffix that Prometheus reserves for counters.
+func GeneratePrometheusReport(result *DiffResult, status ExitStatus) string {
+	hasIssues := 0
+	if result.HasIssues() {
+		hasIssues = 1
+	}
+
+	metrics := []prometheusMetric{
+		{"envquack_missing_variables", "Variables in the example that are missing from the env file.", len(result.Missing)},
+		{"envquack_extra_variables", "Variables in the env file that are not in the example.", len(result.Extra)},
+		{"envquack_required_empty_variables", "Variables marked @required that are set but empty.", len(result.Empty)},
+		{"envquack_empty_variables", "Variables set but empty in the env file (only with --require-values).", len(result.EmptyValues)},
+		{"envquack_invalid_variables", "Values failing a validation annotation.", len(result.Invalid)},
+		{"envquack_changed_variables", "Variables whose value differs from the example (only when comparing values).", len(result.Changed)},
+		{"envquack_deprecated_variables", "Variables marked @deprecated that are still set.", len(result.Deprecated)},
+		{"envquack_possible_secret_variables", "Variables that likely hold real secrets (only with --secrets).", len(result.Secrets)},
+		{"envquack_case_mismatch_variables", "Variables spelled with a different case than the example (only with --case-insensitive).", len(result.CaseMismatch)},
+		{"envquack_duplicate_variables", "Variables assigned more than once in the env file.", len(result.Duplicates)},
+		{"envquack_unresolved_variables", "Variables referencing others that are set nowhere (only with --expand).", len(result.Unresolved)},
+		{"envquack_example_variables", "Variables documented in the example.", result.ExampleTotal},
+		{"envquack_has_issues", "Whether the env file differs from the example (1) or not (0).", hasIssues},
+		{"envquack_exit_code", "Exit code of the check.", status.Code},
+	}
+
+	var out strings.Builder
+	for _, m := range metrics {
+		out.WriteString(fmt.Sprintf("# HELP %s %s\n", m.name, m.help))
+		out.WriteString(fmt.Sprintf("# TYPE %s gauge\n", m.name))
+		out.WriteString(fmt.Sprintf("%s %d\n", m.name, m.value))
+	}
+
+	return out.String()
+}
@@ -0,0 +1,214 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// Reconciliation actions
+const (
+	ActionKeep   = "keep"                // Used and documented
+	ActionAdd    = "add to example"      // Used but undocumented
+	ActionRemove = "remove from example" // Documented but unused
+)
+
+// ReconcileSources are the places a reconciliation reads. Empty paths are skipped.
+type ReconcileSources struct {
+	CodeDir    string // Go source scanned for os.Getenv/os.LookupEnv
+	Compose    string
+	Dockerfile string
+	Example    string
+}
+
+// Reconciliation compares the variables the project actually uses with the
+// ones its example documents
+type Reconciliation struct {
+	Sources   []string         `json:"sources"`   // Usage sources in column order
+	Example   string           `json:"example"`   // The example file
+	Variables []ReconcileEntry `json:"variables"` // Sorted by key
+}
+
+// ReconcileEntry is one variable, where it is used and what to do about it
+type ReconcileEntry struct {
+	Key        string   `json:"key"`
+	UsedIn     []string `json:"used_in"`               // Usage sources that use the variable
+	Documented bool     `json:"documented"`            // Whether the example has it
+	CodeUsages []string `json:"code_usages,omitempty"` // file:line of each read in code
+	Action     string   `json:"action"`
+}
+
+// Keys returns the keys of entries recommending action
+func (r *Reconciliation) Keys(action string) []string {
+	keys := []string{}
+	for _, entry := range r.Variables {
+		if entry.Action == action {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// InSync reports whether every used variable is documented and vice versa
+func (r *Reconciliation) InSync() bool {
+	for _, entry := range r.Variables {
+		if entry.Action != ActionKeep {
+			return false
+		}
+	}
+	return true
+}
+
+// Reconcile collects the variables used by code, compose and the Dockerfile
+// and reconciles them with the example: used and documented variables are
+// kept, used but undocumented ones should be added to the example, and
+// documented but unused ones removed from it. A missing example documents
+// nothing.
+func Reconcile(sources ReconcileSources) (*Reconciliation, error) {
+	r := &Reconciliation{Sources: []string{}, Example: sources.Example, Variables: []ReconcileEntry{}}
+	used := make(map[string][]string)
+	usages := make(map[string][]string)
+
+	add := func(source string, keys []string) {
+		r.Sources = append(r.Sources, source)
+		for _, key := range keys {
+			used[key] = append(used[key], source)
+		}
+	}
+
+	if sources.CodeDir != "" {
+		found, err := parser.ScanGoEnvUsages(sources.CodeDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", sources.CodeDir, err)
+		}
+		keys := []string{}
+		for _, usage := range found {
+			if _, seen := usages[usage.Key]; !seen {
+				keys = append(keys, usage.Key)
+			}
+			usages[usage.Key] = append(usages[usage.Key], fmt.Sprintf("%s:%d", usage.File, usage.Line))
+		}
+		add("code", keys)
+	}
+
+	if sources.Compose != "" {
+		composeInfo, err := loader.ParseComposeFile(sources.Compose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse compose file: %w", err)
+		}
+		add(sources.Compose, composeInfo.GetAllEnvVars())
+	}
+
+	if sources.Dockerfile != "" {
+		dockerfileInfo, err := loader.ParseDockerfile(sources.Dockerfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Dockerfile: %w", err)
+		}
+		add(sources.Dockerfile, dockerfileInfo.GetAllVars())
+	}
+
+	documented := make(parser.EnvVars)
+	if sources.Example != "" {
+		example, err := loader.ParseEnvFile(sources.Example)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", sources.Example, err)
+		}
+		documented = example
+	}
+
+	keys := make(map[string]bool)
+	for key := range used {
+		keys[key] = true
+	}
+	for key := range documented {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		entry := ReconcileEntry{
+			Key:        key,
+			UsedIn:     used[key],
+			Documented: documented.Has(key),
+			CodeUsages: usages[key],
+		}
+		if entry.UsedIn == nil {
+			entry.UsedIn = []string{}
+		}
+
+		switch {
+		case len(entry.UsedIn) > 0 && entry.Documented:
+			entry.Action = ActionKeep
+		case len(entry.UsedIn) > 0:
+			entry.Action = ActionAdd
+		default:
+			entry.Action = ActionRemove
+		}
+		r.Variables = append(r.Variables, entry)
+	}
+
+	sort.Slice(r.Variables, func(i, j int) bool {
+		return r.Variables[i].Key < r.Variables[j].Key
+	})
+
+	return r, nil
+}
+
+// GenerateReconcileReport renders the reconciliation as a table with a
+// column per source and the recommended action for each variable
+func GenerateReconcileReport(r *Reconciliation, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if len(r.Variables) == 0 {
+		report.WriteString("No variables found.\n")
+		return report.String()
+	}
+
+	present, absent := "✓", "·"
+	if !opts.Emoji {
+		present, absent = "x", "-"
+	}
+	mark := func(in bool) string {
+		if in {
+			return present
+		}
+		return absent
+	}
+
+	headers := append([]string{"VARIABLE"}, r.Sources...)
+	headers = append(headers, r.Example, "ACTION")
+	rows := make([][]string, 0, len(r.Variables))
+	for _, entry := range r.Variables {
+		row := []string{entry.Key}
+		for _, source := range r.Sources {
+			in := false
+			for _, s := range entry.UsedIn {
+				in = in || s == source
+			}
+			row = append(row, mark(in))
+		}
+		row = append(row, mark(entry.Documented), entry.Action)
+		rows = append(rows, row)
+	}
+	report.WriteString(renderTable(headers, rows))
+
+	report.WriteString(fmt.Sprintf("\n%d used and documented, %d to add to %s, %d to remove from it\n",
+		len(r.Keys(ActionKeep)), len(r.Keys(ActionAdd)), r.Example, len(r.Keys(ActionRemove))))
+
+	return report.String()
+}
+
+// GenerateReconcileJSON renders the reconciliation as indented JSON
+func GenerateReconcileJSON(r *Reconciliation) (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode reconciliation: %w", err)
+	}
+	return string(data) + "\n", nil
+}
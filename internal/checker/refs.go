@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// RefsDiffResult represents comparison between scanned references and the example
+type RefsDiffResult struct {
+	Referenced   []string // All variables referenced in the scanned files
+	Undocumented []string // Referenced variables missing in the example
+}
+
+// HasIssues returns true if any referenced variable is undocumented
+func (r *RefsDiffResult) HasIssues() bool {
+	return len(r.Undocumented) > 0
+}
+
+// CompareRefsWithExample scans files with a reference pattern and checks the
+// captured variables against the example file
+func CompareRefsWithExample(pattern string, files []string, exampleFile string) (*RefsDiffResult, error) {
+	re, err := parser.CompileRefPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := parser.ExtractRefsFromFiles(re, files)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := loader.ParseEnvFile(exampleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefsDiffResult{
+		Referenced:   refs,
+		Undocumented: []string{},
+	}
+
+	// refs is already sorted
+	for _, ref := range refs {
+		if !example.Has(ref) {
+			result.Undocumented = append(result.Undocumented, ref)
+		}
+	}
+
+	return result, nil
+}
+
+// GenerateRefsReport creates a formatted report for a reference scan
+func GenerateRefsReport(result *RefsDiffResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("Reference scan passed: all %d referenced variables are documented.\n", len(result.Referenced)))
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + fmt.Sprintf("All %d referenced variables are documented.\n", len(result.Referenced)))
+		return report.String()
+	}
+
+	// Header with duck
+	if opts.Plain {
+		report.WriteString(fmt.Sprintf("Reference scan failed: %d undocumented\n\n", len(result.Undocumented)))
+	} else if opts.ShowDuck {
+		report.WriteString(quack.GetAngryDuck() + "\n")
+		report.WriteString("QUACK! " + emoji(opts, "🦆") + "Undocumented variable references detected:\n\n")
+	}
+
+	writeHeading(&report, opts, "🔴", "Variables referenced in scanned files but missing in .env.example", "Undocumented variables")
+
+	writeKeyList(&report, result.Undocumented, "  ", opts)
+	report.WriteString("\n")
+
+	return report.String()
+}
@@ -2,6 +2,8 @@ package checker
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/DuckDHD/EnvQuack/internal/quack"
@@ -9,9 +11,13 @@ import (
 
 // ReportOptions controls report formatting
 type ReportOptions struct {
-	ShowDuck bool
-	Colorize bool
-	Verbose  bool
+	ShowDuck  bool
+	Colorize  bool
+	Emoji     bool
+	Verbose   bool
+	Plain     bool        // Neutral wording with no duck, emoji or jokes
+	MaxIssues int         // Max entries listed per category, 0 for no limit
+	Policy    *ExitPolicy // Decides which findings are failures, nil for the default
 }
 
 // DefaultReportOptions returns sensible defaults
@@ -19,6 +25,7 @@ func DefaultReportOptions() *ReportOptions {
 	return &ReportOptions{
 		ShowDuck: true,
 		Colorize: true,
+		Emoji:    true,
 		Verbose:  false,
 	}
 }
@@ -31,8 +38,15 @@ func GenerateReport(result *DiffResult, opts *ReportOptions) string {
 
 	var report strings.Builder
 
-	if !result.HasIssues() {
-		report.WriteString("✅ All envs aligned.\n")
+	result = visibleResult(result, opts.Policy)
+	verdict := DecideVerdict(result, opts.Policy)
+
+	if verdict == VerdictHappy {
+		if opts.Plain {
+			report.WriteString("Environment check passed: all variables aligned.\n")
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + "All envs aligned.\n")
 		if opts.ShowDuck {
 			report.WriteString("(Your gopher-duck is calm and happy.)\n")
 		}
@@ -40,50 +54,263 @@ func GenerateReport(result *DiffResult, opts *ReportOptions) string {
 	}
 
 	// Header with duck
-	if opts.ShowDuck {
-		report.WriteString(quack.GetAngryDuck() + "\n")
-		report.WriteString("QUACK! 🦆 Environment issues detected:\n\n")
+	if opts.Plain {
+		if verdict == VerdictAngry {
+			report.WriteString(fmt.Sprintf("Environment check failed: %s\n\n", GenerateSummary(result)))
+		} else {
+			report.WriteString(fmt.Sprintf("Environment check passed with warnings: %s\n\n", GenerateSummary(result)))
+		}
+	} else if opts.ShowDuck {
+		if verdict == VerdictAngry {
+			blocking := len(result.Missing) + len(result.Empty) + len(result.EmptyValues) + len(result.Invalid) + len(result.Changed)
+			report.WriteString(quack.GetDuckForSeverity(blocking, len(result.Extra)) + "\n")
+			report.WriteString("QUACK! " + emoji(opts, "🦆") + "Environment issues detected:\n\n")
+		} else {
+			report.WriteString(quack.GetContentDuck() + "\n")
+			report.WriteString("Quack. " + emoji(opts, "🦆") + "No blocking issues, but a few warnings:\n\n")
+		}
+	} else if verdict == VerdictContent {
+		report.WriteString(emoji(opts, "✅") + "No blocking issues, but a few warnings:\n\n")
 	}
 
 	// Missing variables
 	if len(result.Missing) > 0 {
-		if opts.Colorize {
-			report.WriteString("🔴 Missing variables (present in .env.example but not in .env):\n")
-		} else {
-			report.WriteString("Missing variables:\n")
-		}
+		writeHeading(&report, opts, "🔴", "Missing variables (present in .env.example but not in .env)", "Missing variables")
+
+		writeKeyList(&report, result.Missing, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Required variables that are present but empty
+	if len(result.Empty) > 0 {
+		writeHeading(&report, opts, "🟠", "Required but empty (marked @required in .env.example)", "Required but empty")
+
+		writeKeyList(&report, result.Empty, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Variables present but never given a value
+	if len(result.EmptyValues) > 0 {
+		writeHeading(&report, opts, "🟠", "Empty values (present in .env but not filled in)", "Empty values")
+
+		writeKeyList(&report, result.EmptyValues, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Values failing validation annotations
+	if len(result.Invalid) > 0 {
+		writeHeading(&report, opts, "❌", "Invalid values (failing annotations in .env.example)", "Invalid values")
 
-		for _, key := range result.Missing {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
+		lines := make([]string, 0, len(result.Invalid))
+		for _, inv := range result.Invalid {
+			lines = append(lines, fmt.Sprintf("%s (@%s): %s", inv.Key, inv.Annotation, inv.Message))
 		}
+		writeKeyList(&report, lines, "  ", opts)
 		report.WriteString("\n")
 	}
 
 	// Extra variables
 	if len(result.Extra) > 0 {
-		if opts.Colorize {
-			report.WriteString("🟡 Extra variables (present in .env but not in .env.example):\n")
-		} else {
-			report.WriteString("Extra variables:\n")
+		writeHeading(&report, opts, "🟡", "Extra variables (present in .env but not in .env.example)", "Extra variables")
+
+		writeKeyList(&report, result.Extra, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Values that differ from the example
+	if len(result.Changed) > 0 {
+		writeHeading(&report, opts, "🟣", "Values differ from .env.example", "Values differ")
+
+		lines := make([]string, 0, len(result.Changed))
+		for _, c := range result.Changed {
+			lines = append(lines, fmt.Sprintf("%s: %q in .env, %q in .env.example", c.Key, c.Actual, c.Expected))
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Deprecated variables are warnings, not failures
+	writeDeprecations(&report, result.Deprecated, opts)
+
+	// Likely secrets are a heads-up, never a failure
+	if len(result.Secrets) > 0 {
+		writeHeading(&report, opts, "🔐", "Possible secrets in .env (keep it out of version control)", "Possible secrets")
+
+		lines := make([]string, 0, len(result.Secrets))
+		for _, secret := range result.Secrets {
+			lines = append(lines, fmt.Sprintf("%s: %s", secret.Key, strings.Join(secret.Reasons, ", ")))
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Keys matched only by ignoring case are warnings, to be made consistent
+	if len(result.CaseMismatch) > 0 {
+		writeHeading(&report, opts, "🔠", "Keys spelled with a different case in .env", "Case mismatches")
+
+		lines := make([]string, 0, len(result.CaseMismatch))
+		for _, m := range result.CaseMismatch {
+			lines = append(lines, fmt.Sprintf("%s: %s in .env", m.Key, m.EnvKey))
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Keys assigned twice are warnings: the last value silently wins
+	if len(result.Duplicates) > 0 {
+		writeHeading(&report, opts, "⚠️ ", "Duplicate keys in .env (the last value wins)", "Duplicate keys")
+
+		lines := make([]string, 0, len(result.Duplicates))
+		for _, d := range result.Duplicates {
+			line := fmt.Sprintf("%s: lines %s", d.Key, formatLines(d.Lines))
+			// Name the file when several were merged
+			if len(result.Origins) > 0 && d.File != "" {
+				line += " of " + d.File
+			}
+			lines = append(lines, line)
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// References that expanded to nothing are warnings too
+	if len(result.Unresolved) > 0 {
+		writeHeading(&report, opts, "⚠️ ", "Unresolved references in .env (expanded to empty)", "Unresolved references")
+
+		lines := make([]string, 0, len(result.Unresolved))
+		for _, u := range result.Unresolved {
+			lines = append(lines, fmt.Sprintf("%s: %s", u.Key, formatRefs(u.Refs)))
+		}
+		writeKeyList(&report, lines, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Missing keys provided by the OS environment
+	if len(result.FromOS) > 0 && opts.Verbose {
+		writeHeading(&report, opts, "🔵", "Provided by the OS environment (not required in .env)", "Provided by the OS environment")
+
+		writeKeyList(&report, result.FromOS, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Extra keys the example documents as optional
+	if len(result.Optional) > 0 && opts.Verbose {
+		writeHeading(&report, opts, "⚪", "Optional variables set (commented out in .env.example)", "Optional variables set")
+
+		writeKeyList(&report, result.Optional, "  ", opts)
+		report.WriteString("\n")
+	}
+
+	// Where each variable came from when several env files were merged
+	if len(result.Origins) > 0 && opts.Verbose {
+		writeHeading(&report, opts, "📂", "Variable sources (later files override earlier ones)", "Variable sources")
+
+		keys := make([]string, 0, len(result.Origins))
+		for key := range result.Origins {
+			keys = append(keys, key)
 		}
+		sort.Strings(keys)
 
-		for _, key := range result.Extra {
-			report.WriteString(fmt.Sprintf("  - %s\n", key))
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("%s: %s", key, result.Origins[key]))
 		}
+		writeKeyList(&report, lines, "  ", opts)
 		report.WriteString("\n")
 	}
 
+	// Coverage of the example keys
+	if opts.Verbose && result.ExampleTotal > 0 {
+		report.WriteString(fmt.Sprintf("Coverage: %s\n\n", RenderCoverageBar(result.Coverage(), opts)))
+	}
+
 	// Footer with duck message
-	if opts.ShowDuck {
-		report.WriteString("(Your gopher-duck is angry. Fix your .env!)\n")
+	if opts.ShowDuck && !opts.Plain {
+		if verdict == VerdictAngry {
+			report.WriteString("(Your gopher-duck is angry. Fix your .env!)\n")
+		} else {
+			report.WriteString("(Your gopher-duck is content, but keep an eye on these.)\n")
+		}
 	}
 
 	return report.String()
 }
 
+// writeHeading writes a section heading: the long form, led by its icon
+// unless emoji are disabled, when colorized, else the short form
+func writeHeading(report *strings.Builder, opts *ReportOptions, icon, long, short string) {
+	if !opts.Colorize {
+		report.WriteString(short + ":\n")
+		return
+	}
+	report.WriteString(emoji(opts, icon) + long + ":\n")
+}
+
+// emoji returns icon and a space, or nothing when emoji are disabled
+func emoji(opts *ReportOptions, icon string) string {
+	if !opts.Emoji {
+		return ""
+	}
+	return icon + " "
+}
+
+// writeDeprecations writes the deprecated-variable warning section
+func writeDeprecations(report *strings.Builder, deprecated []Deprecation, opts *ReportOptions) {
+	if len(deprecated) == 0 {
+		return
+	}
+
+	writeHeading(report, opts, "⚠️ ", "Deprecated variables still set in .env", "Deprecated variables")
+
+	lines := make([]string, 0, len(deprecated))
+	for _, d := range deprecated {
+		if d.Message != "" {
+			lines = append(lines, fmt.Sprintf("%s (%s)", d.Key, d.Message))
+		} else {
+			lines = append(lines, d.Key)
+		}
+	}
+	writeKeyList(report, lines, "  ", opts)
+	report.WriteString("\n")
+}
+
+// formatRefs renders referenced names as ${A}, ${B}
+func formatRefs(refs []string) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		parts = append(parts, "${"+ref+"}")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatLines renders line numbers as 3, 10
+func formatLines(lines []int) string {
+	parts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		parts = append(parts, strconv.Itoa(line))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeKeyList writes one bullet per key, truncated to opts.MaxIssues entries
+func writeKeyList(report *strings.Builder, keys []string, indent string, opts *ReportOptions) {
+	shown := keys
+	if opts.MaxIssues > 0 && len(keys) > opts.MaxIssues {
+		shown = keys[:opts.MaxIssues]
+	}
+
+	for _, key := range shown {
+		report.WriteString(fmt.Sprintf("%s- %s\n", indent, key))
+	}
+
+	if remaining := len(keys) - len(shown); remaining > 0 {
+		report.WriteString(fmt.Sprintf("%s... and %d more\n", indent, remaining))
+	}
+}
+
 // GenerateSummary creates a brief summary of issues
 func GenerateSummary(result *DiffResult) string {
-	if !result.HasIssues() {
+	if !result.HasIssues() && len(result.Deprecated) == 0 && len(result.Unresolved) == 0 && len(result.Duplicates) == 0 && len(result.Secrets) == 0 && len(result.CaseMismatch) == 0 {
 		return "No issues found"
 	}
 
@@ -91,9 +318,36 @@ func GenerateSummary(result *DiffResult) string {
 	if len(result.Missing) > 0 {
 		parts = append(parts, fmt.Sprintf("%d missing", len(result.Missing)))
 	}
+	if len(result.Empty) > 0 {
+		parts = append(parts, fmt.Sprintf("%d required but empty", len(result.Empty)))
+	}
+	if len(result.EmptyValues) > 0 {
+		parts = append(parts, fmt.Sprintf("%d empty", len(result.EmptyValues)))
+	}
+	if len(result.Invalid) > 0 {
+		parts = append(parts, fmt.Sprintf("%d invalid", len(result.Invalid)))
+	}
 	if len(result.Extra) > 0 {
 		parts = append(parts, fmt.Sprintf("%d extra", len(result.Extra)))
 	}
+	if len(result.Changed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d with different values", len(result.Changed)))
+	}
+	if len(result.Deprecated) > 0 {
+		parts = append(parts, fmt.Sprintf("%d deprecated", len(result.Deprecated)))
+	}
+	if len(result.Secrets) > 0 {
+		parts = append(parts, fmt.Sprintf("%d possible secrets", len(result.Secrets)))
+	}
+	if len(result.CaseMismatch) > 0 {
+		parts = append(parts, fmt.Sprintf("%d with a case mismatch", len(result.CaseMismatch)))
+	}
+	if len(result.Duplicates) > 0 {
+		parts = append(parts, fmt.Sprintf("%d duplicated", len(result.Duplicates)))
+	}
+	if len(result.Unresolved) > 0 {
+		parts = append(parts, fmt.Sprintf("%d with unresolved references", len(result.Unresolved)))
+	}
 
 	return strings.Join(parts, ", ")
 }
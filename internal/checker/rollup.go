@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FileResult is the comparison of one env file in a multi-file run
+type FileResult struct {
+	File   string
+	Result *DiffResult
+}
+
+// Rollup collects per-file results of a multi-file run
+type Rollup struct {
+	Files []FileResult
+}
+
+// Add records the result for a file
+func (r *Rollup) Add(file string, result *DiffResult) {
+	r.Files = append(r.Files, FileResult{File: file, Result: result})
+}
+
+// Clean returns the number of files without issues, judged by policy
+func (r *Rollup) Clean(policy *ExitPolicy) int {
+	clean := 0
+	for _, f := range r.Files {
+		if !visibleResult(f.Result, policy).HasIssues() {
+			clean++
+		}
+	}
+	return clean
+}
+
+// Combined merges every file's findings into one result, so the aggregate
+// exit decision follows the same precedence as a single file
+func (r *Rollup) Combined() *DiffResult {
+	combined := &DiffResult{}
+	for _, f := range r.Files {
+		combined.Missing = append(combined.Missing, f.Result.Missing...)
+		combined.Extra = append(combined.Extra, f.Result.Extra...)
+		combined.Invalid = append(combined.Invalid, f.Result.Invalid...)
+		combined.Empty = append(combined.Empty, f.Result.Empty...)
+		combined.EmptyValues = append(combined.EmptyValues, f.Result.EmptyValues...)
+		combined.Deprecated = append(combined.Deprecated, f.Result.Deprecated...)
+		combined.Changed = append(combined.Changed, f.Result.Changed...)
+		combined.Unresolved = append(combined.Unresolved, f.Result.Unresolved...)
+		combined.Duplicates = append(combined.Duplicates, f.Result.Duplicates...)
+		combined.Secrets = append(combined.Secrets, f.Result.Secrets...)
+		combined.CaseMismatch = append(combined.CaseMismatch, f.Result.CaseMismatch...)
+	}
+	return combined
+}
+
+// DecideRollupExit determines a single exit code for a multi-file run
+func DecideRollupExit(rollup *Rollup, policy *ExitPolicy) ExitStatus {
+	return DecideExit(rollup.Combined(), policy)
+}
+
+// GenerateRollupSummary renders the trailing line of a multi-file run, e.g.
+// "Checked 12 files: 9 clean, 3 with issues (5 missing, 2 extra total)"
+func GenerateRollupSummary(rollup *Rollup, policy *ExitPolicy) string {
+	total := len(rollup.Files)
+	clean := rollup.Clean(policy)
+	combined := visibleResult(rollup.Combined(), policy)
+
+	noun := "files"
+	if total == 1 {
+		noun = "file"
+	}
+
+	summary := fmt.Sprintf("Checked %d %s: %d clean, %d with issues", total, noun, clean, total-clean)
+	if clean == total {
+		return summary + "\n"
+	}
+
+	counts := []string{fmt.Sprintf("%d missing", len(combined.Missing))}
+	if policy != nil && policy.ShowExtra {
+		counts = append(counts, fmt.Sprintf("%d extra", len(combined.Extra)))
+	}
+	if len(combined.Empty) > 0 {
+		counts = append(counts, fmt.Sprintf("%d required but empty", len(combined.Empty)))
+	}
+	if len(combined.EmptyValues) > 0 {
+		counts = append(counts, fmt.Sprintf("%d empty", len(combined.EmptyValues)))
+	}
+	if len(combined.Invalid) > 0 {
+		counts = append(counts, fmt.Sprintf("%d invalid", len(combined.Invalid)))
+	}
+
+	return fmt.Sprintf("%s (%s total)\n", summary, strings.Join(counts, ", "))
+}
+
+// JSONRollup is the structured form of a multi-file run
+type JSONRollup struct {
+	Files      []JSONFileReport `json:"files"`
+	Checked    int              `json:"checked"`
+	Clean      int              `json:"clean"`
+	WithIssues int              `json:"with_issues"`
+	ExitCode   int              `json:"exit_code"`
+	ExitReason ExitReason       `json:"exit_reason"`
+}
+
+// JSONFileReport is one file's report in a multi-file run
+type JSONFileReport struct {
+	File   string          `json:"file"`
+	Report json.RawMessage `json:"report"`
+}
+
+// GenerateRollupJSON renders every file's report and the aggregate verdict as indented JSON
+func GenerateRollupJSON(rollup *Rollup, policy *ExitPolicy) (string, error) {
+	status := DecideRollupExit(rollup, policy)
+	clean := rollup.Clean(policy)
+
+	report := JSONRollup{
+		Files:      make([]JSONFileReport, 0, len(rollup.Files)),
+		Checked:    len(rollup.Files),
+		Clean:      clean,
+		WithIssues: len(rollup.Files) - clean,
+		ExitCode:   status.Code,
+		ExitReason: status.Reason,
+	}
+
+	for _, f := range rollup.Files {
+		fileReport, err := GenerateJSONReport(f.Result, DecideExit(f.Result, policy))
+		if err != nil {
+			return "", err
+		}
+		report.Files = append(report.Files, JSONFileReport{File: f.File, Report: json.RawMessage(fileReport)})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// SchemaViolation is a declared variable that does not meet its schema
+type SchemaViolation struct {
+	Key    string
+	Line   int    // Line of the declaration in the schema
+	Reason string // Why the variable fails, e.g. `"abc" is not an integer`
+}
+
+// SchemaResult holds the schema violations of one env file
+type SchemaResult struct {
+	File       string
+	Schema     string
+	Checked    int // Number of declared variables
+	Violations []SchemaViolation
+}
+
+// HasIssues returns true if any declared variable fails the schema
+func (s *SchemaResult) HasIssues() bool {
+	return len(s.Violations) > 0
+}
+
+// ValidateSchema checks env against the schema fields: required variables
+// must be set to a non-empty value, and every set value must parse as its
+// declared type. Violations are listed in schema order.
+func ValidateSchema(env parser.EnvVars, fields []parser.SchemaField) *SchemaResult {
+	result := &SchemaResult{Checked: len(fields), Violations: []SchemaViolation{}}
+
+	for _, field := range fields {
+		value, exists := env[field.Key]
+		switch {
+		case !exists && field.Required:
+			result.Violations = append(result.Violations, SchemaViolation{field.Key, field.Line, "required but not set"})
+		case strings.TrimSpace(value) == "" && field.Required:
+			result.Violations = append(result.Violations, SchemaViolation{field.Key, field.Line, "required but empty"})
+		case value == "":
+			// Optional and unset
+		default:
+			if err := validateType(value, field.Type); err != nil {
+				result.Violations = append(result.Violations, SchemaViolation{field.Key, field.Line, err.Error()})
+			}
+		}
+	}
+
+	return result
+}
+
+// ValidateEnvFileSchema validates an env file against a .env.schema file
+func ValidateEnvFileSchema(envFile, schemaFile string, parseOpts *parser.ParseOptions) (*SchemaResult, error) {
+	fields, err := parser.ParseSchemaFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := loader.ParseEnvFileWithOptions(envFile, parseOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ValidateSchema(parsed.EnvVars(), fields)
+	result.File = envFile
+	result.Schema = schemaFile
+	return result, nil
+}
+
+// GenerateSchemaReport creates a formatted report for schema violations
+func GenerateSchemaReport(result *SchemaResult, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	if !result.HasIssues() {
+		if opts.Plain {
+			report.WriteString(fmt.Sprintf("Validation passed: %s matches all %d variables of %s.\n", result.File, result.Checked, result.Schema))
+			return report.String()
+		}
+		report.WriteString(emoji(opts, "✅") + fmt.Sprintf("%s matches %s.\n", result.File, result.Schema))
+		if opts.ShowDuck {
+			report.WriteString("(Your gopher-duck approves of these types.)\n")
+		}
+		return report.String()
+	}
+
+	if opts.Plain {
+		report.WriteString(fmt.Sprintf("Validation failed: %d of %d variables violate %s\n\n", len(result.Violations), result.Checked, result.Schema))
+	} else if opts.ShowDuck {
+		report.WriteString(quack.GetDuckForSeverity(len(result.Violations), 0) + "\n")
+		report.WriteString("QUACK! " + emoji(opts, "🦆") + "Schema violations detected:\n\n")
+	}
+
+	if opts.Colorize {
+		report.WriteString(emoji(opts, "❌") + fmt.Sprintf("Variables violating %s:\n", result.Schema))
+	} else {
+		report.WriteString(fmt.Sprintf("Variables violating %s:\n", result.Schema))
+	}
+
+	lines := make([]string, 0, len(result.Violations))
+	for _, v := range result.Violations {
+		lines = append(lines, fmt.Sprintf("%s: %s", v.Key, v.Reason))
+	}
+	writeKeyList(&report, lines, "  ", opts)
+	report.WriteString("\n")
+
+	if opts.ShowDuck && !opts.Plain {
+		report.WriteString("(Your gopher-duck is angry. Fix your .env!)\n")
+	}
+
+	return report.String()
+}
+
+// JSONSchemaReport is the structured form of a schema validation
+type JSONSchemaReport struct {
+	File       string              `json:"file"`
+	Schema     string              `json:"schema"`
+	Checked    int                 `json:"checked"`
+	Violations []JSONSchemaProblem `json:"violations"`
+	HasIssues  bool                `json:"has_issues"`
+}
+
+// JSONSchemaProblem is a schema violation in structured output
+type JSONSchemaProblem struct {
+	Key    string `json:"key"`
+	Line   int    `json:"schema_line"`
+	Reason string `json:"reason"`
+}
+
+// GenerateSchemaJSON renders a schema validation as indented JSON
+func GenerateSchemaJSON(result *SchemaResult) (string, error) {
+	report := JSONSchemaReport{
+		File:       result.File,
+		Schema:     result.Schema,
+		Checked:    result.Checked,
+		Violations: make([]JSONSchemaProblem, 0, len(result.Violations)),
+		HasIssues:  result.HasIssues(),
+	}
+	for _, v := range result.Violations {
+		report.Violations = append(report.Violations, JSONSchemaProblem{Key: v.Key, Line: v.Line, Reason: v.Reason})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// secretKeyMarkers are key name fragments that usually indicate a secret
+var secretKeyMarkers = []string{"SECRET", "PASSWORD", "PASSWD", "TOKEN", "API_KEY", "PRIVATE_KEY", "ACCESS_KEY", "CREDENTIAL"}
+
+// placeholderValues are values that are obviously not real secrets
+var placeholderValues = map[string]bool{
+	"changeme": true, "change_me": true, "todo": true, "xxx": true,
+	"secret": true, "password": true, "example": true, "placeholder": true,
+}
+
+// isSecretKey reports whether a key name looks like it holds a secret
+func isSecretKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(upper, "_KEY")
+}
+
+// isPlaceholderValue reports whether a value is empty or an obvious placeholder
+func isPlaceholderValue(value string) bool {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	if lower == "" || placeholderValues[lower] {
+		return true
+	}
+	return strings.HasPrefix(lower, "<") && strings.HasSuffix(lower, ">")
+}
+
+// hasSecretValues reports whether any secret-looking key holds a real value
+func hasSecretValues(vars parser.EnvVars) bool {
+	for key, value := range vars {
+		if isSecretKey(key) && !isPlaceholderValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretFinding is a variable that likely holds a real secret
+type SecretFinding struct {
+	Key     string
+	Reasons []string // Why it looks secret, e.g. "looks like a JWT"; never the value
+}
+
+// secretValuePatterns are well-known token formats, by description
+var secretValuePatterns = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`^(AKIA|ASIA)[0-9A-Z]{16}$`), "looks like an AWS access key ID"},
+	{regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`), "looks like a JWT"},
+	{regexp.MustCompile(`^-----BEGIN [A-Z ]*PRIVATE KEY-----`), "looks like a private key"},
+}
+
+// Values at least this long with at least this many bits of entropy per
+// character read as random tokens rather than words or settings
+const (
+	secretEntropyMinLength = 20
+	secretEntropyThreshold = 4.0
+)
+
+// DetectSecrets flags variables that likely hold real secrets: keys named
+// like *_KEY, *_SECRET, *_TOKEN or PASSWORD*, and values in a known token
+// format or with high Shannon entropy. Empty, placeholder and reference-only
+// values (${DB_PASSWORD}) are never flagged. Findings are sorted by key.
+func DetectSecrets(vars parser.EnvVars) []SecretFinding {
+	findings := []SecretFinding{}
+
+	for key, value := range vars {
+		trimmed := strings.TrimSpace(value)
+		if isPlaceholderValue(trimmed) || isReferenceOnly(trimmed) {
+			continue
+		}
+
+		reasons := []string{}
+		if isSecretKey(key) {
+			reasons = append(reasons, "key name suggests a secret")
+		}
+		knownFormat := false
+		for _, p := range secretValuePatterns {
+			if p.pattern.MatchString(trimmed) {
+				reasons = append(reasons, p.reason)
+				knownFormat = true
+			}
+		}
+		if !knownFormat && len(trimmed) >= secretEntropyMinLength && shannonEntropy(trimmed) >= secretEntropyThreshold {
+			reasons = append(reasons, "high-entropy value")
+		}
+
+		if len(reasons) > 0 {
+			findings = append(findings, SecretFinding{Key: key, Reasons: reasons})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Key < findings[j].Key
+	})
+	return findings
+}
+
+// valueRefRegex matches the ${VAR} and $VAR references of a value
+var valueRefRegex = regexp.MustCompile(`\$\{[^}]*\}|\$[A-Za-z_][A-Za-z0-9_]*`)
+
+// isReferenceOnly reports whether value is nothing but ${VAR} references
+func isReferenceOnly(value string) bool {
+	return valueRefRegex.MatchString(value) && strings.TrimSpace(valueRefRegex.ReplaceAllString(value, "")) == ""
+}
+
+// shannonEntropy returns the bits of entropy per character of value
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
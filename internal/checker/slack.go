@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+)
+
+// Slack Block Kit limits the report stays within
+const (
+	slackMaxListed    = 20   // Keys listed per category before "…and N more"
+	slackMaxTextChars = 3000 // Characters in a section's text
+)
+
+// slackMoodEmoji is the emoji shown for each duck mood
+var slackMoodEmoji = map[string]string{
+	quack.MoodContent:     ":slightly_smiling_face:",
+	quack.MoodConcerned:   ":thinking_face:",
+	quack.MoodAngry:       ":rage:",
+	quack.MoodOverwhelmed: ":exploding_head:",
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackBlock is a Block Kit layout block; unused fields are omitted
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackMessage is the payload for chat.postMessage or an incoming webhook
+type slackMessage struct {
+	Text   string       `json:"text"` // Notification fallback
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// GenerateSlackReport renders the comparison as Slack Block Kit JSON: a
+// header, count fields, a section per non-empty category and a context line
+// with the duck's mood. Long lists are truncated to stay within Slack's
+// block limits. Extra variables only appear when the policy shows them.
+func GenerateSlackReport(result *DiffResult, status ExitStatus, policy *ExitPolicy) (string, error) {
+	result = visibleResult(result, policy)
+
+	title := "EnvQuack: environment is in sync"
+	if result.HasIssues() {
+		title = "EnvQuack: environment drift detected"
+	}
+
+	message := slackMessage{
+		Text: title,
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{"plain_text", title}},
+		},
+	}
+
+	fields := []slackText{{"mrkdwn", fmt.Sprintf("*Missing*\n%d", len(result.Missing))}}
+	if policy != nil && policy.ShowExtra {
+		fields = append(fields, slackText{"mrkdwn", fmt.Sprintf("*Extra*\n%d", len(result.Extra))})
+	}
+	fields = append(fields,
+		slackText{"mrkdwn", fmt.Sprintf("*Required but empty*\n%d", len(result.Empty))},
+		slackText{"mrkdwn", fmt.Sprintf("*Invalid*\n%d", len(result.Invalid))},
+	)
+	message.Blocks = append(message.Blocks, slackBlock{Type: "section", Fields: fields})
+
+	invalid := make([]string, 0, len(result.Invalid))
+	for _, iv := range result.Invalid {
+		invalid = append(invalid, fmt.Sprintf("%s (%s)", iv.Key, iv.Message))
+	}
+
+	sections := []struct {
+		title string
+		keys  []string
+	}{
+		{"Missing variables", result.Missing},
+		{"Extra variables", result.Extra},
+		{"Required but empty", result.Empty},
+		{"Empty values", result.EmptyValues},
+		{"Invalid values", invalid},
+	}
+	for _, section := range sections {
+		if len(section.keys) == 0 {
+			continue
+		}
+		message.Blocks = append(message.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{"mrkdwn", slackList(section.title, section.keys)},
+		})
+	}
+
+	mood := quack.MoodForSeverity(len(result.Missing), len(result.Extra))
+	message.Blocks = append(message.Blocks, slackBlock{
+		Type: "context",
+		Elements: []slackText{
+			{"mrkdwn", fmt.Sprintf(":duck: The duck is %s %s · exit code %d", mood, slackMoodEmoji[mood], status.Code)},
+		},
+	})
+
+	data, err := json.MarshalIndent(message, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Slack report: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// slackList renders a bold title and a bullet per key, listing at most
+// slackMaxListed keys and never exceeding slackMaxTextChars
+func slackList(title string, keys []string) string {
+	var text strings.Builder
+	text.WriteString("*" + title + "*")
+
+	listed := 0
+	for _, key := range keys {
+		line := "\n• `" + key + "`"
+		// Leave room for the "…and N more" note
+		if listed == slackMaxListed || text.Len()+len(line) > slackMaxTextChars-40 {
+			break
+		}
+		text.WriteString(line)
+		listed++
+	}
+
+	if remaining := len(keys) - listed; remaining > 0 {
+		text.WriteString(fmt.Sprintf("\n…and %d more", remaining))
+	}
+	return text.String()
+}
@@ -0,0 +1,142 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RunState is the findings of a check run, saved so the next run can report
+// what changed since
+type RunState struct {
+	Findings []StateFinding `json:"findings"`
+}
+
+// StateFinding is a finding as saved in the state file
+type StateFinding struct {
+	Category string `json:"category"`
+	Key      string `json:"key"`
+}
+
+// StateChanges is what changed between two runs
+type StateChanges struct {
+	First    bool           // No previous state to compare against
+	New      []StateFinding // Findings that were not there last run
+	Resolved []StateFinding // Findings from last run that are gone
+}
+
+// NewRunState records the findings of result as the policy presents them,
+// so hidden extra variables don't come and go with --show-extra
+func NewRunState(result *DiffResult, policy *ExitPolicy) *RunState {
+	state := &RunState{Findings: []StateFinding{}}
+	for _, f := range result.VisibleFindings(policy) {
+		state.Findings = append(state.Findings, StateFinding{Category: f.Category, Key: f.Key})
+	}
+	sortStateFindings(state.Findings)
+	return state
+}
+
+// LoadRunState reads a state file; a missing file is no previous state (nil)
+func LoadRunState(filename string) (*RunState, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state RunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", filename, err)
+	}
+	return &state, nil
+}
+
+// SaveRunState writes the state file, replacing it atomically
+func SaveRunState(filename string, state *RunState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// DiffRunStates compares the current run against the previous one (nil for none)
+func DiffRunStates(previous, current *RunState) *StateChanges {
+	changes := &StateChanges{New: []StateFinding{}, Resolved: []StateFinding{}}
+	if previous == nil {
+		changes.First = true
+		return changes
+	}
+
+	before := make(map[StateFinding]bool, len(previous.Findings))
+	for _, f := range previous.Findings {
+		before[f] = true
+	}
+	now := make(map[StateFinding]bool, len(current.Findings))
+	for _, f := range current.Findings {
+		now[f] = true
+		if !before[f] {
+			changes.New = append(changes.New, f)
+		}
+	}
+	for _, f := range previous.Findings {
+		if !now[f] {
+			changes.Resolved = append(changes.Resolved, f)
+		}
+	}
+
+	sortStateFindings(changes.New)
+	sortStateFindings(changes.Resolved)
+	return changes
+}
+
+// sortStateFindings orders findings by category, then key
+func sortStateFindings(findings []StateFinding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Category != findings[j].Category {
+			return findings[i].Category < findings[j].Category
+		}
+		return findings[i].Key < findings[j].Key
+	})
+}
+
+// GenerateStateChangesReport renders the "since last run" section
+func GenerateStateChangesReport(changes *StateChanges, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+	writeHeading(&report, opts, "📈", "Since last run", "Since last run")
+
+	switch {
+	case changes.First:
+		report.WriteString("  No previous run recorded; this run is the starting point.\n")
+	case len(changes.New) == 0 && len(changes.Resolved) == 0:
+		report.WriteString("  No changes.\n")
+	default:
+		report.WriteString(fmt.Sprintf("  %d new, %d resolved\n", len(changes.New), len(changes.Resolved)))
+		for _, f := range changes.New {
+			report.WriteString(fmt.Sprintf("  + %s (%s)\n", f.Key, strings.ReplaceAll(f.Category, "_", " ")))
+		}
+		for _, f := range changes.Resolved {
+			report.WriteString(fmt.Sprintf("  - %s (%s)\n", f.Key, strings.ReplaceAll(f.Category, "_", " ")))
+		}
+	}
+
+	return report.String()
+}
@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// SyncSummary accounts for what a sync run changed
+type SyncSummary struct {
+	ExampleTotal   int      `json:"example_total"`     // Keys in the example
+	AlreadyPresent int      `json:"already_present"`   // Example keys already in env before syncing
+	Added          []string `json:"added"`             // Keys appended by the sync
+	StillMissing   []string `json:"still_missing"`     // Keys missing after the sync, should be empty
+	Removed        []string `json:"removed,omitempty"` // Extra keys removed by --prune, nil without it
+	DryRun         bool     `json:"dry_run,omitempty"` // Nothing was written; Added lists what would be
+}
+
+// PruneKeys returns the env keys sync --prune removes: those the example
+// neither sets nor documents, judged like check judges extra variables.
+// Keys the example documents as optional with a commented-out assignment,
+// which it must be parsed with ParseOptions.CommentedKeys to know, are kept,
+// and so are keys matching an ignore pattern.
+func PruneKeys(env parser.EnvVars, example *parser.ParsedFile, ignore []string) []string {
+	result := CompareEnvVars(env, example.EnvVars())
+	ApplyOptionalKeys(result, example.CommentedKeys())
+	ApplyIgnore(result, ignore)
+	return result.Extra
+}
+
+// GenerateSyncSummary creates a short accounting of a sync run
+func GenerateSyncSummary(summary *SyncSummary, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	var report strings.Builder
+
+	heading, added, removed := "Sync summary:", "Newly added:      ", "Removed extra:    "
+	if summary.DryRun {
+		heading, added, removed = "Sync summary (dry run):", "Would be added:   ", "Would be removed: "
+	}
+
+	if opts.Colorize {
+		report.WriteString(emoji(opts, "📋") + heading + "\n")
+	} else {
+		report.WriteString(heading + "\n")
+	}
+	report.WriteString(fmt.Sprintf("  Example variables: %d\n", summary.ExampleTotal))
+	report.WriteString(fmt.Sprintf("  Already present:   %d\n", summary.AlreadyPresent))
+	report.WriteString(fmt.Sprintf("  %s %d\n", added, len(summary.Added)))
+	if summary.Removed != nil {
+		report.WriteString(fmt.Sprintf("  %s %d\n", removed, len(summary.Removed)))
+	}
+	report.WriteString(fmt.Sprintf("  Still missing:     %d\n", len(summary.StillMissing)))
+
+	if len(summary.StillMissing) > 0 {
+		writeKeyList(&report, summary.StillMissing, "    ", opts)
+	}
+
+	return report.String()
+}
+
+// GenerateSyncSummaryJSON renders a sync summary as indented JSON
+func GenerateSyncSummaryJSON(summary *SyncSummary) (string, error) {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode sync summary: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+func TestPruneKeys(t *testing.T) {
+	example, err := parser.ParseEnvReader(strings.NewReader("A=\n# OPTIONAL_FEATURE=\n# Just a comment\n"), ".env.example", &parser.ParseOptions{CommentedKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		env    parser.EnvVars
+		ignore []string
+		want   []string
+	}{
+		{"nothing extra", parser.EnvVars{"A": "1"}, nil, []string{}},
+		{"extra key", parser.EnvVars{"A": "1", "OLD": "x"}, nil, []string{"OLD"}},
+		{"optional key kept", parser.EnvVars{"A": "1", "OPTIONAL_FEATURE": "on", "OLD": "x"}, nil, []string{"OLD"}},
+		{"ignored key kept", parser.EnvVars{"A": "1", "AWS_REGION": "eu", "OLD": "x"}, []string{"AWS_*"}, []string{"OLD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PruneKeys(tt.env, example, tt.ignore); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
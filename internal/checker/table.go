@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ansiCyan colors low-severity table rows
+const ansiCyan = "\033[36m"
+
+// ansiEscapeRegex matches ANSI color sequences, which take no screen space
+var ansiEscapeRegex = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// wideSymbols are the emoji below U+1F000 that terminals draw two columns wide
+var wideSymbols = map[rune]bool{'✅': true, '❌': true, '⛔': true, '⚡': true, '⭐': true}
+
+// displayWidth approximates how many terminal columns s takes: color codes
+// and joiners are free, emoji (including symbols followed by the emoji
+// variation selector) take two columns
+func displayWidth(s string) int {
+	runes := []rune(ansiEscapeRegex.ReplaceAllString(s, ""))
+	width := 0
+	for i, r := range runes {
+		switch {
+		case r == '\uFE0F' || r == '\u200D':
+			// Variation selector and zero width joiner
+		case r >= 0x1F000 || wideSymbols[r]:
+			width += 2
+		case i+1 < len(runes) && runes[i+1] == '\uFE0F':
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// renderTable aligns rows under headers using display widths, so emoji and
+// colored cells line up. Trailing padding is trimmed.
+func renderTable(headers []string, rows [][]string) string {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = displayWidth(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	var table strings.Builder
+	for _, row := range append([][]string{headers}, rows...) {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)))
+		}
+		table.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+
+	return table.String()
+}
+
+// severityStatus returns the icon and ANSI color for a finding severity
+func severityStatus(severity Severity) (string, string) {
+	switch severity {
+	case SeverityError:
+		return "🔴", ansiRed
+	case SeverityWarning:
+		return "🟡", ansiYellow
+	case SeverityLow:
+		return "🔵", ansiCyan
+	default:
+		return "💡", ""
+	}
+}
+
+// GenerateTableReport renders findings as a compact table with one row per
+// finding: Status | Variable | Detail
+func GenerateTableReport(findings []Finding, opts *ReportOptions) string {
+	if opts == nil {
+		opts = DefaultReportOptions()
+	}
+
+	if len(findings) == 0 {
+		if opts.Plain || !opts.Emoji {
+			return "No findings.\n"
+		}
+		return "✅ No findings.\n"
+	}
+
+	shown := findings
+	if opts.MaxIssues > 0 && len(findings) > opts.MaxIssues {
+		shown = findings[:opts.MaxIssues]
+	}
+
+	rows := make([][]string, 0, len(shown))
+	for _, f := range shown {
+		status := strings.ReplaceAll(f.Category, "_", " ")
+
+		icon, color := severityStatus(f.Severity)
+		if opts.Emoji && !opts.Plain {
+			status = icon + " " + status
+		}
+		if opts.Colorize && color != "" {
+			status = color + status + ansiReset
+		}
+
+		rows = append(rows, []string{status, f.Key, f.Message})
+	}
+
+	report := renderTable([]string{"STATUS", "VARIABLE", "DETAIL"}, rows)
+	if remaining := len(findings) - len(shown); remaining > 0 {
+		report += fmt.Sprintf("... and %d more\n", remaining)
+	}
+
+	return report
+}
@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is what a --report-template receives as its dot
+type TemplateData struct {
+	Result   *DiffResult // The raw comparison: .Result.Missing, .Result.Extra, ...
+	Findings []Finding   // Normalized findings: .Source, .Category, .Key, .Severity, .Message
+	Summary  string      // One-line summary, e.g. "2 missing, 1 extra"
+	Status   ExitStatus  // Exit decision: .Status.Code and .Status.Reason
+	Coverage float64     // Percentage of example keys present in env
+}
+
+// templateFuncs are available to report templates in addition to the built-ins
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// GenerateTemplateReport renders an env comparison through a user-provided
+// text/template file
+func GenerateTemplateReport(templateFile string, result *DiffResult, status ExitStatus) (string, error) {
+	content, err := os.ReadFile(templateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read report template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(templateFile)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	data := TemplateData{
+		Result:   result,
+		Findings: result.Findings(),
+		Summary:  GenerateSummary(result),
+		Status:   status,
+		Coverage: result.Coverage(),
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render report template: %w", err)
+	}
+
+	return out.String(), nil
+}
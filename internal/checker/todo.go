@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// DefaultTodoMarkers are the comment markers lint reports as open action items
+var DefaultTodoMarkers = []string{"TODO", "FIXME", "XXX", "HACK"}
+
+// todoMarkerRegex matches any of markers as a whole word, e.g. "TODO:" or
+// "FIXME(alice)" but not "TODOS" or "methodology"
+func todoMarkerRegex(markers []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(markers))
+	for _, marker := range markers {
+		if marker = strings.TrimSpace(marker); marker != "" {
+			quoted = append(quoted, regexp.QuoteMeta(marker))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// lintTodoMarkers returns a rule reporting comments that carry one of
+// markers, such as "# TODO: rotate this key", as informational findings. A
+// marker in the comments directly above an entry is attributed to its key.
+func lintTodoMarkers(markers []string) lintRule {
+	pattern := todoMarkerRegex(markers)
+
+	return func(parsed *parser.ParsedFile) []LintFinding {
+		findings := []LintFinding{}
+		if pattern == nil {
+			return findings
+		}
+
+		for _, comment := range parsed.Comments {
+			marker := pattern.FindString(comment.Text)
+			if marker == "" {
+				continue
+			}
+			findings = append(findings, LintFinding{
+				Rule:     "todo-marker",
+				Severity: SeverityInfo,
+				Key:      documentedKey(parsed, comment.Line),
+				Line:     comment.Line,
+				Message:  fmt.Sprintf("%s comment: %q", marker, comment.Text),
+			})
+		}
+
+		return findings
+	}
+}
+
+// documentedKey returns the key of the entry whose comment block holds the
+// comment at line, or "" for a comment not directly above an entry
+func documentedKey(parsed *parser.ParsedFile, line int) string {
+	commentLines := make(map[int]bool, len(parsed.Comments))
+	for _, comment := range parsed.Comments {
+		commentLines[comment.Line] = true
+	}
+
+	for _, entry := range parsed.Entries {
+		if entry.Line < line {
+			continue
+		}
+		for l := line + 1; l < entry.Line; l++ {
+			if !commentLines[l] {
+				return ""
+			}
+		}
+		return entry.Key
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// KeyTransform rewrites a variable name before comparison
+type KeyTransform func(key string) string
+
+// builtinTransforms are the transforms selectable by name
+var builtinTransforms = map[string]KeyTransform{
+	"camel-to-snake": camelToSnake,
+	"dot-to-snake":   dotToSnake,
+	"upper":          strings.ToUpper,
+}
+
+// TransformNames returns the names of the built-in transforms
+func TransformNames() []string {
+	names := make([]string, 0, len(builtinTransforms))
+	for name := range builtinTransforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTransform returns a built-in transform by name
+func LookupTransform(name string) (KeyTransform, error) {
+	transform, exists := builtinTransforms[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown transform %q (available: %s)", name, strings.Join(TransformNames(), ", "))
+	}
+	return transform, nil
+}
+
+// LoadKeyMap reads explicit renames from a file with one `from -> TO` mapping
+// per line. Keys without a mapping are left unchanged.
+func LoadKeyMap(filename string) (KeyTransform, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key map: %w", err)
+	}
+	defer file.Close()
+
+	renames := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "->", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected `from -> TO`", filename, lineNum)
+		}
+
+		from, to := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("%s:%d: empty key in mapping", filename, lineNum)
+		}
+		renames[from] = to
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading key map: %w", err)
+	}
+
+	return func(key string) string {
+		if renamed, exists := renames[key]; exists {
+			return renamed
+		}
+		return key
+	}, nil
+}
+
+// ChainTransforms applies transforms left to right, skipping nil ones
+func ChainTransforms(transforms ...KeyTransform) KeyTransform {
+	return func(key string) string {
+		for _, transform := range transforms {
+			if transform != nil {
+				key = transform(key)
+			}
+		}
+		return key
+	}
+}
+
+// TransformKeys returns a copy of vars with every key rewritten
+func TransformKeys(vars parser.EnvVars, transform KeyTransform) parser.EnvVars {
+	transformed := make(parser.EnvVars, len(vars))
+	for key, value := range vars {
+		transformed[transform(key)] = value
+	}
+	return transformed
+}
+
+// camelToSnake converts camelCase, PascalCase and dotted keys to
+// SCREAMING_SNAKE, e.g. dbHost -> DB_HOST and APIKey -> API_KEY
+func camelToSnake(key string) string {
+	runes := []rune(key)
+	var out strings.Builder
+
+	for i, r := range runes {
+		if r == '.' || r == '-' {
+			out.WriteRune('_')
+			continue
+		}
+
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				out.WriteRune('_')
+			}
+		}
+
+		out.WriteRune(unicode.ToUpper(r))
+	}
+
+	return out.String()
+}
+
+// dotToSnake converts dotted or dashed keys to SCREAMING_SNAKE, e.g. db.host -> DB_HOST
+func dotToSnake(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
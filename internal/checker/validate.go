@@ -0,0 +1,120 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// InvalidValue is an env value that fails a validation annotation from the example
+type InvalidValue struct {
+	Key        string
+	Annotation string // Annotation that failed, e.g. "json"
+	Message    string
+}
+
+// valueValidator checks a value against an annotation's argument text
+type valueValidator func(value, arg string) error
+
+// valueValidators are run for env values whose example entry carries the annotation
+var valueValidators = map[string]valueValidator{
+	"json":      validateJSON,
+	"type":      validateType,
+	"length":    validateLength,
+	"minlength": validateMinLength,
+	"charset":   validateCharset,
+}
+
+// ApplyValidations records env values that fail the validation annotations of
+// their example entry. Keys that are missing or empty in env are skipped.
+func ApplyValidations(result *DiffResult, env parser.EnvVars, example *parser.ParsedFile) {
+	seen := make(map[string]bool)
+
+	for _, entry := range example.Entries {
+		value, exists := env[entry.Key]
+		if !exists || value == "" || seen[entry.Key] {
+			continue
+		}
+		seen[entry.Key] = true
+
+		names := make([]string, 0, len(entry.Annotations))
+		for name := range entry.Annotations {
+			if valueValidators[name] != nil {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := valueValidators[name](value, entry.Annotations[name]); err != nil {
+				result.Invalid = append(result.Invalid, InvalidValue{
+					Key:        entry.Key,
+					Annotation: name,
+					Message:    err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// ApplyRequiredValues records keys annotated @required in the example that
+// are present in env but empty, which presence alone would hide
+func ApplyRequiredValues(result *DiffResult, env parser.EnvVars, example *parser.ParsedFile) {
+	seen := make(map[string]bool)
+
+	for _, entry := range example.Entries {
+		if !entry.Annotations.Has("required") || seen[entry.Key] {
+			continue
+		}
+		seen[entry.Key] = true
+
+		if value, exists := env[entry.Key]; exists && strings.TrimSpace(value) == "" {
+			result.Empty = append(result.Empty, entry.Key)
+		}
+	}
+
+	sort.Strings(result.Empty)
+}
+
+// ApplyEmptyValues records example keys that are present in env but empty,
+// such as the KEY= lines a sync leaves behind. Keys already reported as
+// required but empty are skipped, and with skipEmptyExample so are keys the
+// example leaves empty too.
+func ApplyEmptyValues(result *DiffResult, env, example parser.EnvVars, skipEmptyExample bool) {
+	required := make(map[string]bool, len(result.Empty))
+	for _, key := range result.Empty {
+		required[key] = true
+	}
+
+	for key, sample := range example {
+		value, exists := env[key]
+		if !exists || strings.TrimSpace(value) != "" || required[key] {
+			continue
+		}
+		if skipEmptyExample && strings.TrimSpace(sample) == "" {
+			continue
+		}
+		result.EmptyValues = append(result.EmptyValues, key)
+	}
+
+	sort.Strings(result.EmptyValues)
+}
+
+// validateJSON checks that value is well-formed JSON, reporting where parsing failed
+func validateJSON(value, _ string) error {
+	var decoded interface{}
+	err := json.Unmarshal([]byte(value), &decoded)
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("invalid JSON at position %d: %v", syntaxErr.Offset, syntaxErr)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
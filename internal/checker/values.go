@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// ValueMismatch is a key set in both env and example with different values
+type ValueMismatch struct {
+	Key      string
+	Expected string // Value in the example
+	Actual   string // Value in env
+}
+
+// ApplyValueComparison records keys whose env value differs from the example
+// value. Empty example values are placeholders and never mismatch. Booleans
+// match whatever their spelling, so "true" equals "1", "yes" and "on". To
+// compare resolved values, expand both sides first (see resolveValueRefs).
+func ApplyValueComparison(result *DiffResult, env, example parser.EnvVars) {
+	for key, expected := range example {
+		actual, ok := env[key]
+		if !ok || expected == "" {
+			continue
+		}
+
+		if actual != expected && !sameBool(actual, expected) {
+			result.Changed = append(result.Changed, ValueMismatch{Key: key, Expected: expected, Actual: actual})
+		}
+	}
+
+	sort.Slice(result.Changed, func(i, j int) bool {
+		return result.Changed[i].Key < result.Changed[j].Key
+	})
+}
+
+// resolveValueRefs expands the references on each side against that side's
+// own variables, so `${HOST}/api` matches `localhost/api` when the example
+// sets HOST=localhost. It follows parser.ExpandEnvVars: references set
+// nowhere expand to empty, and circular ones are an error. env is left as it
+// is when it was expanded already.
+func resolveValueRefs(env parser.EnvVars, example *parser.ParsedFile, envExpanded bool) (parser.EnvVars, parser.EnvVars, error) {
+	if !envExpanded {
+		var err error
+		if env, _, err = parser.ExpandVars(env, ".env", nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	exampleVars, _, err := parser.ExpandEnvVars(example, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return env, exampleVars, nil
+}
+
+// MaskValue stands in for a value that may be a secret, giving away only its
+// length, e.g. [hidden, 12 chars]
+func MaskValue(value string) string {
+	return fmt.Sprintf("[hidden, %d chars]", utf8.RuneCountInString(value))
+}
+
+// MaskValues masks both values of each mismatch, so reports and the JSON,
+// CSV and GitHub output can't leak them
+func MaskValues(changed []ValueMismatch) {
+	for i := range changed {
+		changed[i].Expected = MaskValue(changed[i].Expected)
+		changed[i].Actual = MaskValue(changed[i].Actual)
+	}
+}
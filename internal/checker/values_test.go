@@ -0,0 +1,198 @@
+package checker
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+func TestApplyValueComparison(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     parser.EnvVars
+		example parser.EnvVars
+		want    []ValueMismatch
+	}{
+		{
+			name:    "differing value",
+			env:     parser.EnvVars{"DATABASE_URL": "postgres://localhost"},
+			example: parser.EnvVars{"DATABASE_URL": "postgres://prod"},
+			want:    []ValueMismatch{{Key: "DATABASE_URL", Expected: "postgres://prod", Actual: "postgres://localhost"}},
+		},
+		{
+			name:    "equal value",
+			env:     parser.EnvVars{"PORT": "8080"},
+			example: parser.EnvVars{"PORT": "8080"},
+		},
+		{
+			name:    "empty placeholder",
+			env:     parser.EnvVars{"API_KEY": "real-key"},
+			example: parser.EnvVars{"API_KEY": ""},
+		},
+		{
+			name:    "boolean spelling",
+			env:     parser.EnvVars{"DEBUG": "yes"},
+			example: parser.EnvVars{"DEBUG": "true"},
+		},
+		{
+			name:    "missing key",
+			env:     parser.EnvVars{},
+			example: parser.EnvVars{"PORT": "8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CompareEnvVars(tt.env, tt.example)
+			ApplyValueComparison(result, tt.env, tt.example)
+			if len(result.Changed) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(result.Changed, tt.want) {
+				t.Errorf("Changed = %+v, want %+v", result.Changed, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareModeGatesValues(t *testing.T) {
+	dir := t.TempDir()
+	env := writeFile(t, dir, ".env", "PORT=9090\n")
+	example := writeFile(t, dir, ".env.example", "PORT=8080\n")
+
+	tests := []struct {
+		mode     CompareMode
+		want     string
+		dontWant string
+	}{
+		{"", "All envs aligned", "Values differ"},
+		{CompareKeys, "All envs aligned", "Values differ"},
+		{CompareValues, "🟣 Values differ", "All envs aligned"},
+		{CompareBoth, "🟣 Values differ", "All envs aligned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			opts := DefaultCompareOptions()
+			opts.Mode = tt.mode
+			opts.ShowValues = true
+
+			result, err := CompareEnvFiles(env, example, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The report shows whatever the comparison found
+			report := GenerateReport(result, nil)
+			if !strings.Contains(report, tt.want) {
+				t.Errorf("report lacks %q:\n%s", tt.want, report)
+			}
+			if strings.Contains(report, tt.dontWant) {
+				t.Errorf("report has %q:\n%s", tt.dontWant, report)
+			}
+			if tt.mode.ComparesValues() && !strings.Contains(report, `PORT: "9090" in .env, "8080" in .env.example`) {
+				t.Errorf("report does not list both values:\n%s", report)
+			}
+		})
+	}
+}
+
+func TestCompareEnvFilesMasksValues(t *testing.T) {
+	dir := t.TempDir()
+	env := writeFile(t, dir, ".env", "API_KEY=sk-live-secret\n")
+	example := writeFile(t, dir, ".env.example", "API_KEY=sk-test\n")
+
+	tests := []struct {
+		name       string
+		showValues bool
+		want       ValueMismatch
+		leaks      bool
+	}{
+		{"masked by default", false, ValueMismatch{Key: "API_KEY", Expected: "[hidden, 7 chars]", Actual: "[hidden, 14 chars]"}, false},
+		{"shown on request", true, ValueMismatch{Key: "API_KEY", Expected: "sk-test", Actual: "sk-live-secret"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultCompareOptions()
+			opts.Mode = CompareBoth
+			opts.ShowValues = tt.showValues
+
+			result, err := CompareEnvFiles(env, example, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Changed) != 1 || result.Changed[0] != tt.want {
+				t.Fatalf("Changed = %+v, want [%+v]", result.Changed, tt.want)
+			}
+
+			jsonReport, err := GenerateJSONReport(result, DecideExit(result, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			csvReport, err := GenerateCSVReport(result.Findings())
+			if err != nil {
+				t.Fatal(err)
+			}
+			github := GenerateGitHubReport([]LocatedFinding{{Finding: result.Findings()[0]}})
+
+			for format, output := range map[string]string{"json": jsonReport, "csv": csvReport, "github": github} {
+				if got := strings.Contains(output, "sk-live-secret"); got != tt.leaks {
+					t.Errorf("%s output contains the value = %v, want %v:\n%s", format, got, tt.leaks, output)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveBeforeCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		example string
+		changed []string
+		cycle   bool
+	}{
+		{"templated example matches", "API=localhost/api\n", "HOST=localhost\nAPI=${HOST}/api\n", nil, false},
+		{"templated env matches", "HOST=localhost\nAPI=${HOST}/api\n", "API=localhost/api\n", nil, false},
+		{"resolved values differ", "API=remote/api\n", "HOST=localhost\nAPI=${HOST}/api\n", []string{"API"}, false},
+		{"default", "API=localhost/api\n", "API=${HOST:-localhost}/api\n", nil, false},
+		{"self reference in env", "A=${A}${A}\nB=1\n", "A=x\n", nil, true},
+		{"two-key cycle in example", "A=1\n", "A=${B}\nB=${A}\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			env := writeFile(t, dir, ".env", tt.env)
+			example := writeFile(t, dir, ".env.example", tt.example)
+
+			opts := DefaultCompareOptions()
+			opts.Mode = CompareValues
+			opts.ResolveRefs = true
+
+			result, err := CompareEnvFiles(env, example, opts)
+			var cycle *parser.CycleError
+			if tt.cycle {
+				if !errors.As(err, &cycle) {
+					t.Fatalf("error = %v, want a *parser.CycleError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var changed []string
+			for _, c := range result.Changed {
+				changed = append(changed, c.Key)
+			}
+			if !reflect.DeepEqual(changed, tt.changed) {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
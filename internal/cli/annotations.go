@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+// annotationsCmd represents the annotations command
+var annotationsCmd = &cobra.Command{
+	Use:   "annotations",
+	Short: "Print the schema documented by .env.example's annotations",
+	Long: `Annotations prints every key of .env.example with its @annotations
+(@required, @type, @enum, ...), its doc comment and its line, including
+commented-out optional keys.
+
+Use --format json for a manifest to generate docs from or feed other
+validators.`,
+	RunE: runAnnotations,
+}
+
+func init() {
+	rootCmd.AddCommand(annotationsCmd)
+}
+
+func runAnnotations(cmd *cobra.Command, args []string) error {
+	if err := checkFileExists(exampleFile); err != nil {
+		return fmt.Errorf("example file error: %w", err)
+	}
+
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := checker.CollectAnnotations(exampleFile, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "text":
+		fmt.Print(checker.GenerateAnnotationsReport(manifest))
+	case "json":
+		report, err := checker.GenerateAnnotationsJSON(manifest)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	default:
+		return fmt.Errorf("unsupported format %q for annotations (use text or json)", outputFormat)
+	}
+
+	return nil
+}
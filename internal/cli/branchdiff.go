@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+var branchDiffCompose bool
+
+// branchDiffCmd represents the branch-diff command
+var branchDiffCmd = &cobra.Command{
+	Use:   "branch-diff <base> <head>",
+	Short: "Show the configuration a branch adds or removes versus another",
+	Long: `Branch-diff reads .env.example as it is at two git refs, without checking
+either out, and lists the variables the head ref adds and removes relative to
+the base ref, e.g. the new configuration a release branch requires.
+
+Paths are relative to the repository root. Use --with-compose to also compare
+the variables the compose file references. A file that exists at only one of
+the refs is reported as new or deleted.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBranchDiff,
+}
+
+func init() {
+	branchDiffCmd.Flags().BoolVar(&branchDiffCompose, "with-compose", false, "also compare the variables referenced by the compose file")
+
+	rootCmd.AddCommand(branchDiffCmd)
+}
+
+func runBranchDiff(cmd *cobra.Command, args []string) error {
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+
+	compose := ""
+	if branchDiffCompose {
+		compose = composeFile
+	}
+
+	diff, err := checker.CompareBranches(args[0], args[1], exampleFile, compose, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "text":
+		fmt.Print(checker.GenerateBranchDiffReport(diff, newReportOptions(false, verbose)))
+	case "json":
+		report, err := checker.GenerateBranchDiffJSON(diff)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	default:
+		return fmt.Errorf("unsupported format %q for branch-diff (use text or json)", outputFormat)
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// writeFile writes content to name in dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// since flag values outlive a run of the shared rootCmd
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	t.Helper()
+	reset := func(f *pflag.Flag) {
+		if env, ok := f.Value.(*envFlag); ok {
+			*env = envFlag{}
+			envPatterns = []string{defaultEnvFile}
+			envFile = defaultEnvFile
+		} else if slice, ok := f.Value.(pflag.SliceValue); ok {
+			var values []string
+			if def := strings.Trim(f.DefValue, "[]"); def != "" {
+				values = strings.Split(def, ",")
+			}
+			if err := slice.Replace(values); err != nil {
+				t.Fatalf("resetting --%s: %v", f.Name, err)
+			}
+		} else if err := f.Value.Set(f.DefValue); err != nil {
+			t.Fatalf("resetting --%s: %v", f.Name, err)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(t, sub)
+	}
+}
+
+// run executes envquack in-process with args, plain and uncolored,
+// discarding its output, and returns the error Execute returns
+func run(t *testing.T, args ...string) error {
+	t.Helper()
+	_, err := capture(t, append(args, "--no-duck", "--no-color")...)
+	return err
+}
+
+// capture executes envquack in-process with exactly args and returns what it
+// wrote to stdout along with the error Execute returns
+func capture(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	out, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+
+	resetFlags(t, rootCmd)
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(out)
+	runErr := Execute()
+
+	written, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(written), runErr
+}
+
+// exitCode returns the code err ends the run with, as main does
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Code
+	}
+	return 1
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "A=\nB=\n")
+	complete := writeFile(t, dir, "complete.env", "A=1\nB=2\n")
+	missing := writeFile(t, dir, "missing.env", "A=1\n")
+	extra := writeFile(t, dir, "extra.env", "A=1\nB=2\nC=3\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"aligned", []string{"--env", complete}, 0},
+		{"missing", []string{"--env", missing}, 1},
+		{"missing tolerated", []string{"--env", missing, "--fail-on", "extra"}, 0},
+		{"extra hidden", []string{"--env", extra}, 0},
+		{"extra shown", []string{"--env", extra, "--show-extra"}, 1},
+		{"extra shown but tolerated", []string{"--env", extra, "--show-extra", "--fail-on", "missing"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"check", "--example", example}, tt.args...)
+			err := run(t, args...)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exit code = %d (%v), want %d", got, err, tt.want)
+			}
+			var exit *ExitError
+			if err != nil && !errors.As(err, &exit) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommandsReturnExitErrors(t *testing.T) {
+	dir := t.TempDir()
+	env := writeFile(t, dir, ".env", "PORT=08080\n")
+	schema := writeFile(t, dir, ".env.schema", "PORT:url:required\n")
+	example := writeFile(t, dir, ".env.example", "UNUSED=\n")
+	writeFile(t, dir, "main.go", "package main\n\nimport \"os\"\n\nfunc main() { _ = os.Getenv(\"USED\") }\n")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"validate", []string{"validate", "--env", env, "--schema", schema}},
+		{"lint", []string{"lint", "--env", env}},
+		{"reconcile", []string{"reconcile", dir, "--example", example}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(t, tt.args...)
+			var exit *ExitError
+			if !errors.As(err, &exit) || exit.Code != 1 {
+				t.Errorf("%s = %v, want an ExitError with code 1", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestRelativeRootIsAppliedOnce(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, project, ".env.example", "A=\nB=\n")
+	writeFile(t, project, ".env", "A=1\n")
+	writeFile(t, project, ".env.local", "B=1\n")
+	t.Chdir(dir)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"single env file", []string{"--env", ".env"}, 1},
+		{"merged env files", []string{"--env", ".env", "--env", ".env.local"}, 0},
+		{"merged env glob", []string{"--env", ".env*", "--env", ".env.local"}, 0},
+		{"env file already rooted", []string{"--env", "project/.env", "--env", "project/.env.local"}, 0},
+		{"several env files", []string{".env", ".env.local"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"check", "--root", "project"}, tt.args...)
+			err := run(t, args...)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exit code = %d (%v), want %d", got, err, tt.want)
+			}
+			var exit *ExitError
+			if err != nil && !errors.As(err, &exit) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOnlyServicesMatchingNothingFails(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "docker-compose.yml", "services:\n  web:\n    environment:\n      - PORT=${PORT}\n")
+	writeFile(t, dir, ".env", "PORT=1\n")
+	writeFile(t, dir, ".env.example", "PORT=\n")
+
+	out, err := capture(t, "audit", "--root", dir, "--only-services", "web,wbe", "--no-duck", "--no-color")
+	if code := exitCode(err); code != 1 {
+		t.Errorf("text audit exit code = %d (%v), want 1", code, err)
+	}
+	if !strings.Contains(out, "--only-services patterns matched no service: wbe") {
+		t.Errorf("text audit output lacks the unknown service:\n%s", out)
+	}
+
+	err = run(t, "audit", "--root", dir, "--only-services", "wbe", "--format", "csv")
+	if err == nil || isExitError(err) || !strings.Contains(err.Error(), "wbe") {
+		t.Errorf("csv audit = %v, want an error naming wbe", err)
+	}
+
+	if err := run(t, "audit", "--root", dir, "--only-services", "web"); err != nil {
+		t.Errorf("audit of a known service = %v", err)
+	}
+}
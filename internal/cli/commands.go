@@ -3,22 +3,82 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/DuckDHD/EnvQuack/internal/checker"
 	"github.com/DuckDHD/EnvQuack/internal/parser"
 	"github.com/DuckDHD/EnvQuack/internal/quack"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	envFile        string
-	exampleFile    string
-	composeFile    string
-	dockerfileFile string
-	verbose        bool
-	noColor        bool
-	noDuck         bool
+	envFile           string
+	exampleFile       string
+	composeFile       string
+	dockerfileFile    string
+	verbose           bool
+	noColor           bool
+	noDuck            bool
+	noEmoji           bool
+	plain             bool
+	containerName     string
+	secretsDir        string
+	inherit           string
+	imageName         string
+	pullImage         bool
+	scanRefs          string
+	parallel          int
+	stateFile         string
+	failOn            []string
+	detectSecrets     bool
+	watchMode         bool
+	watchInterval     time.Duration
+	ignorePatterns    []string
+	packageJSON       string
+	makefile          string
+	k8sFiles          []string
+	helmFiles         []string
+	helmKeys          string
+	openAPIFile       string
+	interactive       bool
+	maxIssues         int
+	useOSEnv          bool
+	allowExtra        bool
+	showExtra         bool
+	outputFormat      string
+	checkGitignore    bool
+	checkDockerignore bool
+	onlyServices      []string
+	requireAllSvcs    bool
+	transformName     string
+	transformMap      string
+	transformSide     string
+	iniMode           bool
+	envFormat         string
+	envEncoding       string
+	emptyAsMissing    bool
+	rootDir           string
+	compareValues     bool
+	compareMode       string
+	showValues        bool
+	outputFile        string
+	findingOrder      string
+	noSort            bool
+	reportTmpl        string
+	resolveRefs       bool
+	expandRefs        bool
+	caseInsensitive   bool
+	requireValues     bool
+	skipEmptyExample  bool
+	configFile        string
+	duckFile          string
+	remoteTimeout     time.Duration
+	remoteMaxSize     int
+	remoteMaxRedir    int
 )
 
 // rootCmd represents the base command
@@ -26,6 +86,40 @@ var rootCmd = &cobra.Command{
 	Use:   "envquack",
 	Short: "Environment Variable Drift Detective 🦆",
 	Long:  quack.GetBanner() + "\nEnvQuack helps you keep your environment variables in sync.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Settings from .envquack.yaml fill in flags not given explicitly;
+		// self-check reports config problems itself
+		if cmd.Name() != "self-check" {
+			if err := applyConfig(cmd); err != nil {
+				return err
+			}
+		}
+
+		// Plain mode is a preset that strips all whimsy from the output
+		if plain {
+			noDuck = true
+			noColor = true
+			noEmoji = true
+		}
+
+		if err := loadDuckFile(); err != nil {
+			return err
+		}
+
+		// Checks within one invocation often parse the same files
+		useParseCache()
+
+		if remoteTimeout <= 0 || remoteMaxSize <= 0 || remoteMaxRedir < 0 {
+			return fmt.Errorf("remote limits must be positive")
+		}
+		parser.SetRemoteLimits(&parser.RemoteLimits{
+			Timeout:      remoteTimeout,
+			MaxBytes:     int64(remoteMaxSize) << 20,
+			MaxRedirects: remoteMaxRedir,
+		})
+
+		return applyRootDir()
+	},
 }
 
 // checkCmd represents the check command
@@ -36,7 +130,45 @@ var checkCmd = &cobra.Command{
 
 This includes:
 - Missing variables (present in example but not in .env)  
-- Extra variables (present in .env but not in example)`,
+- Extra variables (present in .env but not in example), only with --show-extra
+
+Use --container to compare a running container's environment instead of .env.
+
+Use --secrets-dir to compare a directory of per-variable secret files instead.
+
+Use --image to see which example variables an image bakes in with ENV and
+which must be supplied at runtime (add --pull to fetch a missing image).
+
+Use --interactive to be prompted for each missing variable (with the example's
+comments as a hint); answers are appended to .env and empty answers skip.
+
+Use --k8s to compare the keys of Kubernetes ConfigMap and Secret manifests
+instead of .env. Secret values are never decoded.
+
+Use --helm to compare the keys of Helm values files, flattened so that
+database.host becomes DATABASE_HOST (or stays database.host with
+--helm-keys dotted).
+
+Use --package-json to check variables used by npm scripts: inline KEY=value
+assignments (including cross-env) are collected, and $VAR references that
+are not assigned inline must be documented in the example.
+
+Use --makefile to check variables a Makefile takes from the environment:
+$(VAR) references it never assigns, and $$VAR in recipes.
+
+Pass env files (or quoted globs) as arguments to check several at once, each
+against the example; a roll-up line and a single exit code cover the run:
+
+  envquack check .env.staging 'deploy/*.env'
+
+Use --openapi to check that the variables in an OpenAPI document's server
+URLs (https://{host}:{port}/) are documented; variables with a default are
+optional.
+
+Use --scan-refs with a regex and a list of files to treat every captured
+name as a required variable, e.g. for nginx templates or systemd units:
+
+  envquack check --scan-refs '\$\{([A-Z_]+)\}' nginx.conf.template`,
 	RunE: runCheck,
 }
 
@@ -52,31 +184,93 @@ var auditCmd = &cobra.Command{
 - Analyzes Dockerfile ARG and ENV instructions
 - Shows service-by-service breakdown
 
-This gives you a complete picture of your environment configuration.`,
-	RunE: runAudit,
-}
-var syncCmd = &cobra.Command{
-	Use:   "sync",
-	Short: "Sync missing variables from .env.example to .env",
-	Long: `Sync adds missing variables from .env.example to your .env file with empty values.
+This gives you a complete picture of your environment configuration.
+
+Use --only-services web,worker to restrict the compose check to the named
+services. Names are globs ('worker-*') combined with OR; a leading ! excludes
+matches, e.g. 'worker-*,!worker-2'. A pattern matching no service is an error.
 
-This helps you quickly scaffold your .env file based on the example.`,
-	RunE: runSync,
+Use --require-all-services to fail unless every compose service has all of its
+required variables; incomplete services are listed worst first.`,
+	RunE: runAudit,
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&envFile, "env", ".env", "path to .env file")
-	rootCmd.PersistentFlags().StringVar(&exampleFile, "example", ".env.example", "path to .env.example file")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file with flag defaults (default .envquack.yaml, skipped if missing)")
+	rootCmd.PersistentFlags().StringVar(&rootDir, "root", "", "project directory that relative file paths are resolved against")
+	envFile = defaultEnvFile
+	rootCmd.PersistentFlags().Var(&envFlag{}, "env", "path to .env file, or an http(s) URL; check merges repeated --env files and globs, later files overriding")
+	rootCmd.PersistentFlags().DurationVar(&remoteTimeout, "remote-timeout", 10*time.Second, "give up fetching an http(s) file after this long")
+	rootCmd.PersistentFlags().IntVar(&remoteMaxSize, "remote-max-size", 10, "largest http(s) file to download, in MB")
+	rootCmd.PersistentFlags().IntVar(&remoteMaxRedir, "remote-max-redirects", 5, "redirects to follow when fetching an http(s) file")
+	rootCmd.PersistentFlags().StringVar(&exampleFile, "example", ".env.example", "path to .env.example file, git:<ref>:<path> to read it from a git ref, or an http(s) URL")
 	rootCmd.PersistentFlags().StringVar(&composeFile, "compose", "docker-compose.yml", "path to docker-compose file")
 	rootCmd.PersistentFlags().StringVar(&dockerfileFile, "dockerfile", "Dockerfile", "path to Dockerfile")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
 	rootCmd.PersistentFlags().BoolVar(&noDuck, "no-duck", false, "disable ASCII duck art")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "use plain ASCII instead of emoji and Unicode symbols")
+	rootCmd.PersistentFlags().BoolVar(&plain, "plain", false, "professional output: no duck, emoji or jokes (alias: --professional)")
+	rootCmd.PersistentFlags().StringVar(&duckFile, "duck-file", "", "text file with your own duck art for report headers (default $"+duckFileEnv+"); a line of %% separates the happy and the angry duck")
+	rootCmd.PersistentFlags().IntVar(&maxIssues, "max-issues", 0, "list at most N entries per report category (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&showExtra, "show-extra", false, "report extra variables and fail on them (alias: --strict)")
+	rootCmd.PersistentFlags().BoolVar(&allowExtra, "allow-extra", false, "with --show-extra, treat extra variables as warnings instead of failures")
+	rootCmd.PersistentFlags().BoolVar(&emptyAsMissing, "treat-empty-as-missing", false, "count keys set to an empty value (KEY= or KEY=\"\") in .env as missing")
+	rootCmd.PersistentFlags().BoolVar(&requireValues, "require-values", false, "report example keys that are present in .env but empty, and fail on them")
+	rootCmd.PersistentFlags().BoolVar(&skipEmptyExample, "skip-empty-example", false, "with --require-values, accept empty values for keys the example leaves empty too")
+	rootCmd.PersistentFlags().BoolVar(&useOSEnv, "use-os-env", false, "treat example values like ${USER} as satisfied when the OS environment provides them")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "output format: text, json, csv, table, env, env-extra, prometheus, slack, checkstyle or github (support varies by command)")
+	rootCmd.PersistentFlags().StringVar(&compareMode, "compare-mode", string(checker.CompareKeys), "what to compare: keys (presence), values (of keys set on both sides) or both")
+	rootCmd.PersistentFlags().BoolVar(&compareValues, "compare-values", false, "shorthand for --compare-mode both")
+	rootCmd.PersistentFlags().BoolVar(&showValues, "show-values", false, "print the values of differing keys instead of masking them, in every format")
+	rootCmd.PersistentFlags().BoolVar(&resolveRefs, "resolve-before-compare", false, "expand ${VAR} references on both sides before comparing values (implies --compare-mode both unless set)")
+	rootCmd.PersistentFlags().BoolVar(&expandRefs, "expand", false, "expand ${VAR} references in .env values before checking, against .env itself (and the OS environment with --use-os-env)")
+	rootCmd.PersistentFlags().BoolVar(&caseInsensitive, "case-insensitive", false, "match .env keys to example keys whatever their case, warning about each different spelling")
+	rootCmd.PersistentFlags().StringSliceVar(&ignorePatterns, "ignore", nil, "key names or globs (AWS_*) never reported as missing or extra, added to those in .quackignore")
+	rootCmd.PersistentFlags().StringVar(&findingOrder, "order", "alpha", "order of reported variables: alpha, or file for the declaration order in the example (and .env for extras)")
+	rootCmd.PersistentFlags().BoolVar(&noSort, "no-sort", false, "shorthand for --order file")
+	rootCmd.PersistentFlags().StringVar(&transformName, "transform", "", "rename keys before comparing: "+strings.Join(checker.TransformNames(), ", "))
+	rootCmd.PersistentFlags().StringVar(&transformMap, "transform-map", "", "file of explicit 'from -> TO' key renames, applied after --transform")
+	rootCmd.PersistentFlags().StringVar(&transformSide, "transform-side", "both", "which keys to transform: both, env or example")
+	rootCmd.PersistentFlags().StringVar(&envFormat, "env-format", "dotenv", "how to read the env file: dotenv or docker (docker run --env-file rules)")
+	rootCmd.PersistentFlags().StringVar(&envEncoding, "encoding", parser.DefaultEncoding, "encoding of the env files: utf8, latin1, latin9, windows-1252, utf16le or utf16be")
+	rootCmd.PersistentFlags().BoolVar(&iniMode, "ini", false, "parse env files as INI: keys under [section] become SECTION_KEY")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+
+	// Check flags
+	checkCmd.Flags().StringVar(&containerName, "container", "", "compare a container's environment (via docker inspect) instead of .env")
+	checkCmd.Flags().StringVar(&secretsDir, "secrets-dir", "", "compare a directory of per-variable files (e.g. /run/secrets) instead of .env")
+	checkCmd.Flags().StringVar(&inherit, "inherit", "", "merge the same named env files of parent directories, nearest winning, up to the git root (git) or filesystem root (root)")
+	checkCmd.Flags().Lookup("inherit").NoOptDefVal = parser.InheritGitRoot
+	checkCmd.Flags().StringVar(&imageName, "image", "", "show which example variables an image bakes in (ENV) and which must be supplied at runtime")
+	checkCmd.Flags().BoolVar(&pullImage, "pull", false, "with --image, pull the image if it is not present locally")
+	checkCmd.Flags().BoolVar(&interactive, "interactive", false, "prompt for a value for each missing variable and write the answers to .env")
+	checkCmd.Flags().StringSliceVar(&k8sFiles, "k8s", nil, "compare the keys of Kubernetes ConfigMap/Secret manifests instead of .env (repeatable)")
+	checkCmd.Flags().StringVar(&outputFile, "output", "", "write the report to this file instead of stdout (replaced atomically)")
+	checkCmd.Flags().StringVar(&reportTmpl, "report-template", "", "render the report with this Go text/template file instead of --format")
+	checkCmd.Flags().StringSliceVar(&helmFiles, "helm", nil, "compare the flattened keys of Helm values files instead of .env (repeatable, later files override)")
+	checkCmd.Flags().StringVar(&helmKeys, "helm-keys", checker.HelmKeysEnv, "how nested Helm values are flattened: env (DATABASE_HOST) or dotted (database.host)")
+	checkCmd.Flags().StringVar(&openAPIFile, "openapi", "", "check OpenAPI server URL variables ({host}, {port}) against the example")
+	checkCmd.Flags().StringVar(&packageJSON, "package-json", "", "check env used by package.json scripts against the example")
+	checkCmd.Flags().StringVar(&makefile, "makefile", "", "check env a Makefile takes from the environment against the example")
+	checkCmd.Flags().StringVar(&stateFile, "diff-against-previous", "", "report what changed since the last run, whose findings are kept in this state file")
+	checkCmd.Flags().Lookup("diff-against-previous").NoOptDefVal = defaultStateFile
+	checkCmd.Flags().IntVar(&parallel, "parallel", 0, "compare up to N env files at once when checking several (0 = GOMAXPROCS)")
+	checkCmd.Flags().BoolVar(&watchMode, "watch", false, "keep running and re-check whenever .env, .env.example or the compose file changes (Ctrl-C to stop)")
+	checkCmd.Flags().DurationVar(&watchInterval, "watch-interval", 500*time.Millisecond, "how often --watch polls the files for changes")
+	checkCmd.Flags().BoolVar(&detectSecrets, "secrets", false, "warn about variables that likely hold real secrets (secret-looking names, known token formats, high-entropy values)")
+	checkCmd.Flags().StringSliceVar(&failOn, "fail-on", nil, "finding categories that fail the run, the rest only warn: "+strings.Join(checker.FailOnCategories, ", ")+" (default all)")
+	checkCmd.Flags().StringVar(&scanRefs, "scan-refs", "", "regex with one capture group; variables it captures in the given files must be in the example")
+
+	// Audit flags
+	auditCmd.Flags().StringSliceVar(&onlyServices, "only-services", nil, "restrict the compose check to these services (comma separated globs, !pattern excludes)")
+	auditCmd.Flags().BoolVar(&requireAllSvcs, "require-all-services", false, "fail unless every compose service has all its required variables, naming the incomplete ones")
+	auditCmd.Flags().BoolVar(&checkGitignore, "check-gitignore", false, "warn when compose env_files with secret-looking values are not gitignored")
+	auditCmd.Flags().BoolVar(&checkDockerignore, "check-dockerignore", false, "warn when compose env_files with secret-looking values are not excluded by .dockerignore")
 
 	// Add commands
 	rootCmd.AddCommand(checkCmd)
-	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(auditCmd)
 }
 
@@ -85,227 +279,1082 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// ExitError ends a run with a non-zero exit code once its report is written.
+// It carries no message of its own; the caller exits with Code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// exitStatus returns the error that ends a run with code, keeping cobra from
+// printing it or the usage after the report
+func exitStatus(code int) error {
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	return &ExitError{Code: code}
+}
+
 func runCheck(cmd *cobra.Command, args []string) error {
 	// Check if files exist
 	if err := checkFileExists(exampleFile); err != nil {
 		return fmt.Errorf("example file error: %w", err)
 	}
 
-	if err := checkFileExists(envFile); err != nil {
-		return fmt.Errorf("env file error: %w", err)
+	if cmd.Flags().Changed("fail-on") {
+		categories, err := checker.ParseFailOn(failOn)
+		if err != nil {
+			return err
+		}
+		failOn = categories
+	}
+
+	// Only the text report of one env comparison shows what changed
+	if stateFile != "" && (outputFormat != "text" || reportTmpl != "" || len(args) > 0 || scanRefs != "" || packageJSON != "" || makefile != "" || openAPIFile != "" || imageName != "") {
+		return fmt.Errorf("--diff-against-previous only works with text output of a single env comparison")
+	}
+
+	if watchMode {
+		return runWatch(args)
+	}
+
+	return checkOnce(args)
+}
+
+// checkOnce runs a single check with the current flags and prints its report
+func checkOnce(args []string) error {
+	if scanRefs != "" {
+		return runScanRefs(args)
+	}
+
+	if packageJSON != "" {
+		return runPackageScripts()
+	}
+
+	if makefile != "" {
+		return runMakefile()
+	}
+
+	if openAPIFile != "" {
+		return runOpenAPI()
 	}
 
-	// Compare files
-	result, err := checker.CompareEnvFiles(envFile, exampleFile)
+	if imageName != "" {
+		return runImage()
+	}
+
+	compareOpts, err := newCompareOptions()
 	if err != nil {
-		return fmt.Errorf("failed to compare files: %w", err)
+		return err
+	}
+
+	if interactive && (len(k8sFiles) > 0 || len(helmFiles) > 0 || containerName != "" || secretsDir != "" || outputFormat != "text" || len(args) > 0 || multipleEnvFiles()) {
+		return fmt.Errorf("--interactive only works with text output against an env file")
+	}
+
+	if len(args) > 0 {
+		return runCheckFiles(args, compareOpts)
+	}
+
+	var result *checker.DiffResult
+	if len(helmFiles) > 0 {
+		// Compare the flattened keys of Helm values
+		result, err = checker.CompareHelmValues(helmFiles, helmKeys, exampleFile, compareOpts)
+		if err != nil {
+			return fmt.Errorf("failed to compare Helm values: %w", err)
+		}
+	} else if len(k8sFiles) > 0 {
+		// Compare the keys of ConfigMaps and Secrets
+		result, err = checker.CompareK8sResources(k8sFiles, exampleFile, compareOpts)
+		if err != nil {
+			return fmt.Errorf("failed to compare Kubernetes resources: %w", err)
+		}
+	} else if secretsDir != "" {
+		// Compare the files of a secrets mount
+		result, err = checker.CompareSecretsDir(secretsDir, exampleFile, compareOpts)
+		if err != nil {
+			return fmt.Errorf("failed to compare secrets directory: %w", err)
+		}
+	} else if containerName != "" {
+		// Compare the container's live environment
+		result, err = checker.CompareContainerEnv(containerName, exampleFile, compareOpts)
+		if err != nil {
+			return fmt.Errorf("failed to compare container environment: %w", err)
+		}
+	} else {
+		if !multipleEnvFiles() {
+			if err := checkFileExists(envFile); err != nil {
+				return fmt.Errorf("env file error: %w", err)
+			}
+		}
+
+		// Compare files
+		result, err = compareEnvFile(compareOpts)
+		if err != nil {
+			return fmt.Errorf("failed to compare files: %w", err)
+		}
+
+		// Guided fix, then report on the updated file
+		if interactive && len(result.Missing) > 0 {
+			if err := runInteractiveFix(result); err != nil {
+				return err
+			}
+			result, err = compareEnvFile(compareOpts)
+			if err != nil {
+				return fmt.Errorf("failed to compare files: %w", err)
+			}
+		}
 	}
 
+	status := checker.DecideExit(result, newExitPolicy())
+
 	// Generate and display report
-	opts := &checker.ReportOptions{
-		ShowDuck: !noDuck,
-		Colorize: !noColor,
-		Verbose:  verbose,
+	var report string
+	switch {
+	case reportTmpl != "":
+		report, err = checker.GenerateTemplateReport(rootPath(reportTmpl), result, status)
+		if err != nil {
+			return err
+		}
+	case outputFormat == "text":
+		opts := newReportOptions(!noDuck, verbose)
+		report = checker.GenerateReport(result, opts)
+		if stateFile != "" {
+			changes, err := runStateDiff(result)
+			if err != nil {
+				return err
+			}
+			report = strings.TrimRight(report, "\n") + "\n\n" + checker.GenerateStateChangesReport(changes, opts)
+		}
+	case outputFormat == "json":
+		report, err = checker.GenerateJSONReport(result, status)
+		if err != nil {
+			return err
+		}
+	case outputFormat == "csv":
+		report, err = checker.GenerateCSVReport(result.VisibleFindings(newExitPolicy()))
+		if err != nil {
+			return err
+		}
+	case outputFormat == "table":
+		report = checker.GenerateTableReport(result.VisibleFindings(newExitPolicy()), newReportOptions(false, verbose))
+	case outputFormat == "env":
+		// Just the missing keys, ready to paste into .env
+		report = parser.FormatEnvBlock(result.Missing, nil)
+	case outputFormat == "env-extra":
+		// Just the undocumented key names, never their values, for pruning .env
+		report = parser.FormatKeyList(result.Extra)
+	case outputFormat == "prometheus":
+		report = checker.GeneratePrometheusReport(result, status)
+	case outputFormat == "checkstyle":
+		findings, err := locateFindings(result)
+		if err != nil {
+			return err
+		}
+		report, err = checker.GenerateCheckstyleReport(findings)
+		if err != nil {
+			return err
+		}
+	case outputFormat == "github":
+		findings, err := locateFindings(result)
+		if err != nil {
+			return err
+		}
+		report = checker.GenerateGitHubReport(findings)
+	case outputFormat == "slack":
+		report, err = checker.GenerateSlackReport(result, status, newExitPolicy())
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format %q for check (use text, json, csv, table, env, env-extra, prometheus, slack, checkstyle or github)", outputFormat)
 	}
 
-	report := checker.GenerateReport(result, opts)
-	fmt.Print(report)
+	if err := writeReport(report); err != nil {
+		return err
+	}
 
 	// Exit with error code if issues found
-	if result.HasIssues() {
-		os.Exit(1)
+	if !status.OK() {
+		return exitStatus(status.Code)
 	}
 
 	return nil
 }
 
-func runSync(cmd *cobra.Command, args []string) error {
-	// Check if example file exists
-	if err := checkFileExists(exampleFile); err != nil {
-		return fmt.Errorf("example file error: %w", err)
+// locateFindings points check findings at their lines for the checkstyle
+// and github formats, in the env file each key was set in
+func locateFindings(result *checker.DiffResult) ([]checker.LocatedFinding, error) {
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return checker.LocateFindings(result.VisibleFindings(newExitPolicy()), result.Locations, exampleFile, parseOpts)
+}
+
+// compareEnvFile compares --env against the example, merged with the same
+// named files of its parent directories under --inherit
+func compareEnvFile(opts *checker.CompareOptions) (*checker.DiffResult, error) {
+	if multipleEnvFiles() {
+		if inherit != "" {
+			return nil, fmt.Errorf("--inherit needs a single --env file")
+		}
+		files, err := expandEnvFiles(envPatterns)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if err := checkFileExists(file); err != nil {
+				return nil, fmt.Errorf("env file error: %w", err)
+			}
+		}
+		return checker.CompareMergedEnvFiles(files, exampleFile, opts)
+	}
+
+	if inherit == "" {
+		return checker.CompareEnvFiles(envFile, exampleFile, opts)
 	}
 
-	// Parse example file
-	example, err := parser.ParseEnvFile(exampleFile)
+	if parser.IsGitSource(envFile) || parser.IsRemoteSource(envFile) {
+		return nil, fmt.Errorf("--inherit needs a local env file")
+	}
+	files, err := parser.FindInheritedEnvFiles(envFile, inherit)
 	if err != nil {
-		return fmt.Errorf("failed to parse example file: %w", err)
+		return nil, err
 	}
+	return checker.CompareMergedEnvFiles(files, exampleFile, opts)
+}
 
-	// Parse existing env file (create if doesn't exist)
-	var env parser.EnvVars
-	if _, err := os.Stat(envFile); os.IsNotExist(err) {
-		env = make(parser.EnvVars)
-		fmt.Printf("Creating new %s file...\n", envFile)
-	} else {
-		env, err = parser.ParseEnvFile(envFile)
+// defaultStateFile keeps the findings of the last run for --diff-against-previous
+const defaultStateFile = ".envquack-state.json"
+
+// runStateDiff compares the findings of this run with the state file and
+// replaces the state file with them
+func runStateDiff(result *checker.DiffResult) (*checker.StateChanges, error) {
+	filename := rootPath(stateFile)
+	previous, err := checker.LoadRunState(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	current := checker.NewRunState(result, newExitPolicy())
+	if err := checker.SaveRunState(filename, current); err != nil {
+		return nil, err
+	}
+
+	return checker.DiffRunStates(previous, current), nil
+}
+
+// writeReport prints the report, or writes it to --output. The file is
+// replaced atomically so collectors reading it never see a partial report.
+func writeReport(report string) error {
+	if outputFile == "" {
+		fmt.Print(report)
+		return nil
+	}
+
+	tmp := outputFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(report), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmp, outputFile); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// runCheckFiles compares each env file (or glob match) against the example and
+// finishes with a roll-up and a single exit code for the whole run
+func runCheckFiles(patterns []string, compareOpts *checker.CompareOptions) error {
+	files, err := expandEnvFiles(patterns)
+	if err != nil {
+		return err
+	}
+
+	if parallel < 0 {
+		return fmt.Errorf("--parallel must be at least 0, got %d", parallel)
+	}
+
+	for _, file := range files {
+		if err := checkFileExists(file); err != nil {
+			return fmt.Errorf("env file error: %w", err)
+		}
+	}
+
+	policy := newExitPolicy()
+	rollup, err := checker.CompareEnvFilesParallel(files, exampleFile, compareOpts, parallel)
+	if err != nil {
+		return err
+	}
+
+	status := checker.DecideRollupExit(rollup, policy)
+
+	var report string
+	switch outputFormat {
+	case "text":
+		var text strings.Builder
+		for _, f := range rollup.Files {
+			fmt.Fprintf(&text, "==> %s\n", f.File)
+			// The duck would repeat for every file, so only the roll-up gets a verdict
+			text.WriteString(checker.GenerateReport(f.Result, newReportOptions(false, verbose)))
+			text.WriteString("\n")
+		}
+		text.WriteString(checker.GenerateRollupSummary(rollup, policy))
+		report = text.String()
+	case "json":
+		report, err = checker.GenerateRollupJSON(rollup, policy)
+		if err != nil {
+			return err
+		}
+	case "prometheus":
+		report = checker.GeneratePrometheusReport(rollup.Combined(), status)
+	case "checkstyle":
+		findings, err := locateRollupFindings(rollup)
 		if err != nil {
-			return fmt.Errorf("failed to parse env file: %w", err)
+			return err
 		}
+		report, err = checker.GenerateCheckstyleReport(findings)
+		if err != nil {
+			return err
+		}
+	case "github":
+		findings, err := locateRollupFindings(rollup)
+		if err != nil {
+			return err
+		}
+		report = checker.GenerateGitHubReport(findings)
+	default:
+		return fmt.Errorf("unsupported format %q for checking several env files (use text, json, prometheus, checkstyle or github)", outputFormat)
+	}
+
+	if err := writeReport(report); err != nil {
+		return err
 	}
 
-	// Find missing variables
-	result := checker.CompareEnvVars(env, example)
+	if !status.OK() {
+		return exitStatus(status.Code)
+	}
+
+	return nil
+}
 
-	if len(result.Missing) == 0 {
-		fmt.Println("✅ No missing variables to sync.")
-		if !noDuck {
-			fmt.Println("(Your gopher-duck is already happy!)")
+// locateRollupFindings points the findings of every file of a multi-file run
+// at their lines, in file order
+func locateRollupFindings(rollup *checker.Rollup) ([]checker.LocatedFinding, error) {
+	located := []checker.LocatedFinding{}
+	for _, f := range rollup.Files {
+		findings, err := locateFindings(f.Result)
+		if err != nil {
+			return nil, err
 		}
-		return nil
+		located = append(located, findings...)
+	}
+	return located, nil
+}
+
+// expandEnvFiles resolves env file arguments against --root and expands globs,
+// keeping the order given and dropping duplicates
+func expandEnvFiles(patterns []string) ([]string, error) {
+	files := []string{}
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		pattern = rootPath(pattern)
+
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			matches, err = filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid env file pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no env files match %q", pattern)
+			}
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// runPackageScripts checks env used by package.json scripts against the example
+func runPackageScripts() error {
+	if err := checkFileExists(packageJSON); err != nil {
+		return fmt.Errorf("package.json error: %w", err)
+	}
+
+	result, err := checker.ComparePackageScriptsWithExample(packageJSON, exampleFile)
+	if err != nil {
+		return fmt.Errorf("failed to check package.json scripts: %w", err)
+	}
+
+	return writeFindingsReport(result.Findings(), packageJSON, func(opts *checker.ReportOptions) string {
+		return checker.GeneratePackageScriptsReport(result, opts)
+	})
+}
+
+// runMakefile checks env a Makefile relies on against the example
+func runMakefile() error {
+	if err := checkFileExists(makefile); err != nil {
+		return fmt.Errorf("Makefile error: %w", err)
+	}
+
+	result, err := checker.CompareMakefileWithExample(makefile, exampleFile)
+	if err != nil {
+		return fmt.Errorf("failed to check Makefile: %w", err)
 	}
 
-	// Show sync message
-	if !noDuck {
-		fmt.Println(quack.GetSyncMessage())
+	return writeFindingsReport(result.Findings(), makefile, func(opts *checker.ReportOptions) string {
+		return checker.GenerateMakefileReport(result, opts)
+	})
+}
+
+// runOpenAPI checks OpenAPI server variables against the example
+func runOpenAPI() error {
+	if err := checkFileExists(openAPIFile); err != nil {
+		return fmt.Errorf("OpenAPI document error: %w", err)
 	}
-	fmt.Printf("Adding %d missing variables to %s:\n", len(result.Missing), envFile)
 
-	// Append missing variables to env file
-	file, err := os.OpenFile(envFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	result, err := checker.CompareOpenAPIWithExample(openAPIFile, exampleFile)
 	if err != nil {
-		return fmt.Errorf("failed to open env file for writing: %w", err)
+		return fmt.Errorf("failed to check OpenAPI servers: %w", err)
 	}
-	defer file.Close()
 
-	// Add a separator comment if file already has content
-	if len(env) > 0 {
-		file.WriteString("\n# Added by envquack sync\n")
+	return writeFindingsReport(result.Findings(), openAPIFile, func(opts *checker.ReportOptions) string {
+		return checker.GenerateOpenAPIReport(result, opts)
+	})
+}
+
+// runImage reports which example variables an image bakes in. It is
+// informational: variables left to runtime are expected, not failures.
+func runImage() error {
+	result, err := checker.CompareImageEnv(imageName, exampleFile, pullImage)
+	if err != nil {
+		return fmt.Errorf("failed to inspect image: %w", err)
 	}
 
-	for _, key := range result.Missing {
-		line := fmt.Sprintf("%s=\n", key)
-		if _, err := file.WriteString(line); err != nil {
-			return fmt.Errorf("failed to write variable %s: %w", key, err)
+	return writeFindingsReport(result.Findings(), "", func(opts *checker.ReportOptions) string {
+		return checker.GenerateImageReport(result, opts)
+	})
+}
+
+// writeFindingsReport renders the findings of a check in --format, the text
+// format through text, writes the report like any check does and fails the
+// run as --fail-on decides. GitHub annotations point at file, when not empty.
+func writeFindingsReport(findings []checker.Finding, file string, text func(*checker.ReportOptions) string) error {
+	status := checker.DecideFindingsExit(findings, newExitPolicy())
+
+	var report string
+	var err error
+	switch outputFormat {
+	case "text":
+		report = text(newReportOptions(!noDuck, verbose))
+	case "json":
+		report, err = checker.GenerateFindingsJSON(findings, status)
+		if err != nil {
+			return err
+		}
+	case "csv":
+		report, err = checker.GenerateCSVReport(findings)
+		if err != nil {
+			return err
 		}
-		fmt.Printf("  + %s\n", key)
+	case "table":
+		report = checker.GenerateTableReport(findings, newReportOptions(false, verbose))
+	case "github":
+		located := make([]checker.LocatedFinding, 0, len(findings))
+		for _, f := range findings {
+			located = append(located, checker.LocatedFinding{Finding: f, File: file})
+		}
+		report = checker.GenerateGitHubReport(located)
+	default:
+		return fmt.Errorf("unsupported format %q for this check (use text, json, csv, table or github)", outputFormat)
+	}
+
+	if err := writeReport(report); err != nil {
+		return err
 	}
 
-	fmt.Printf("\n✅ Successfully synced %d variables!\n", len(result.Missing))
-	fmt.Println("Don't forget to set the actual values in your .env file.")
+	if !status.OK() {
+		return exitStatus(status.Code)
+	}
 
 	return nil
 }
 
+// runScanRefs checks variables captured by --scan-refs against the example
+func runScanRefs(files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("--scan-refs requires at least one file to scan")
+	}
+
+	result, err := checker.CompareRefsWithExample(scanRefs, files, exampleFile)
+	if err != nil {
+		return fmt.Errorf("failed to scan references: %w", err)
+	}
+
+	return writeFindingsReport(result.Findings(), "", func(opts *checker.ReportOptions) string {
+		return checker.GenerateRefsReport(result, opts)
+	})
+}
+
+// printSection prints a step of the text audit: its title, when set, then
+// report indented under it, and a blank line
+func printSection(title, report string) {
+	if title != "" {
+		fmt.Println(title)
+	}
+	if report = strings.TrimSuffix(report, "\n"); report != "" {
+		for _, line := range strings.Split(report, "\n") {
+			if line != "" {
+				line = "  " + line
+			}
+			fmt.Println(line)
+		}
+	}
+	fmt.Println()
+}
+
 func runAudit(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔍 Running comprehensive environment audit...\n")
+	if outputFormat != "text" {
+		return runAuditStructured()
+	}
+
+	fmt.Print(icon("🔍") + "Running comprehensive environment audit...\n\n")
 
 	hasErrors := false
 
 	// 1. Basic .env vs .env.example check
 	if err := checkFileExists(exampleFile); err == nil && fileExists(envFile) {
-		fmt.Println("📋 Checking .env vs .env.example:")
-		result, err := checker.CompareEnvFiles(envFile, exampleFile)
+		var section strings.Builder
+		result, err := compareEnvFiles()
 		if err != nil {
-			fmt.Printf("  ❌ Error: %v\n", err)
+			fmt.Fprintf(&section, "%sError: %v\n", icon("❌"), err)
 			hasErrors = true
 		} else {
-			opts := &checker.ReportOptions{
-				ShowDuck: false,
-				Colorize: !noColor,
-				Verbose:  false,
-			}
+			opts := newReportOptions(false, false)
 
-			if !result.HasIssues() {
-				fmt.Println("  ✅ Basic env check passed")
+			verdict := checker.DecideVerdict(result, opts.Policy)
+			if verdict == checker.VerdictHappy {
+				section.WriteString(icon("✅") + "Basic env check passed\n")
 			} else {
-				fmt.Print("  " + strings.ReplaceAll(checker.GenerateReport(result, opts), "\n", "\n  "))
-				hasErrors = true
+				section.WriteString(checker.GenerateReport(result, opts))
+				hasErrors = hasErrors || verdict == checker.VerdictAngry
 			}
 		}
-		fmt.Println()
+		printSection(icon("📋")+"Checking .env vs .env.example:", section.String())
 	}
 
 	// 2. Docker Compose environment check
 	if err := checkFileExists(composeFile); err == nil {
-		fmt.Println("🐳 Checking docker-compose environment requirements:")
-
 		envFiles := []string{}
 		if fileExists(envFile) {
 			envFiles = append(envFiles, envFile)
 		}
 
-		composeResult, err := checker.CompareComposeWithEnv(composeFile, envFiles)
+		var section strings.Builder
+		composeResult, err := compareCompose(envFiles)
 		if err != nil {
-			fmt.Printf("  ❌ Error parsing compose file: %v\n", err)
+			fmt.Fprintf(&section, "%sError checking compose file: %v\n", icon("❌"), err)
 			hasErrors = true
 		} else {
-			opts := &checker.ReportOptions{
-				ShowDuck: false,
-				Colorize: !noColor,
-				Verbose:  verbose,
-			}
+			opts := newReportOptions(false, verbose)
 
 			if !composeResult.HasIssues() {
-				fmt.Println("  ✅ Docker Compose check passed")
+				section.WriteString(icon("✅") + "Docker Compose check passed\n")
 			} else {
-				report := checker.GenerateComposeReport(composeResult, opts)
-				fmt.Print("  " + strings.ReplaceAll(report, "\n", "\n  "))
+				section.WriteString(checker.GenerateComposeReport(composeResult, opts))
 				hasErrors = true
 			}
+
+			// Service-level gate
+			if requireAllSvcs {
+				gaps := composeResult.IncompleteServices()
+				section.WriteString(checker.GenerateServiceGateReport(gaps, opts))
+				hasErrors = hasErrors || len(gaps) > 0
+			}
+
+			// Informational: services that may have been left unconfigured
+			if verbose {
+				section.WriteString(checker.GenerateUnconfiguredServicesReport(composeResult.UnconfiguredServices, opts))
+			}
+
+			// Informational: env_files shared by several services, all of
+			// them with --verbose, otherwise only those with conflicting values
+			section.WriteString(checker.GenerateSharedEnvFilesReport(composeResult.SharedEnvFiles, verbose, opts))
 		}
-		fmt.Println()
+		printSection(icon("🐳")+"Checking docker-compose environment requirements:", section.String())
 	} else {
-		fmt.Printf("  ℹ️  No docker-compose.yml found, skipping compose check\n\n")
+		printSection("", icon("ℹ️ ")+"No docker-compose.yml found, skipping compose check")
 	}
 
 	// 3. Dockerfile environment check
 	if err := checkFileExists(dockerfileFile); err == nil {
-		fmt.Println("🐋 Checking Dockerfile environment requirements:")
-
 		envFiles := []string{}
 		if fileExists(envFile) {
 			envFiles = append(envFiles, envFile)
 		}
 
+		var section strings.Builder
 		dockerfileResult, err := checker.CompareDockerfileWithEnv(dockerfileFile, envFiles)
 		if err != nil {
-			fmt.Printf("  ❌ Error parsing Dockerfile: %v\n", err)
+			fmt.Fprintf(&section, "%sError parsing Dockerfile: %v\n", icon("❌"), err)
 			hasErrors = true
 		} else {
-			opts := &checker.ReportOptions{
-				ShowDuck: false,
-				Colorize: !noColor,
-				Verbose:  verbose,
-			}
+			opts := newReportOptions(false, verbose)
 
 			if !dockerfileResult.HasIssues() {
-				fmt.Println("  ✅ Dockerfile check passed")
+				section.WriteString(icon("✅") + "Dockerfile check passed\n")
 			} else {
-				report := checker.GenerateDockerfileReport(dockerfileResult, opts)
-				fmt.Print("  " + strings.ReplaceAll(report, "\n", "\n  "))
+				section.WriteString(checker.GenerateDockerfileReport(dockerfileResult, opts))
 				hasErrors = true
 			}
 		}
-		fmt.Println()
+		printSection(icon("🐋")+"Checking Dockerfile environment requirements:", section.String())
 	} else {
-		fmt.Printf("  ℹ️  No Dockerfile found, skipping Dockerfile check\n\n")
+		printSection("", icon("ℹ️ ")+"No Dockerfile found, skipping Dockerfile check")
 	}
 
 	// 4. Summary
-	if !noDuck {
+	if plain {
 		if hasErrors {
-			fmt.Println(quack.GetAngryDuck())
-			fmt.Println("QUACK! 🦆 Audit found issues that need attention!")
+			fmt.Println("Audit failed: issues need attention.")
 		} else {
-			fmt.Println(quack.GetHappyDuck())
-			fmt.Println("✅ Audit passed! Your environment is well organized.")
+			fmt.Println("Audit passed: environment is consistent.")
+		}
+	} else if !noDuck {
+		if hasErrors {
+			fmt.Println(quack.GetRandomDuck(quack.MoodAngry))
+			fmt.Println("QUACK! " + icon("🦆") + "Audit found issues that need attention!")
+		} else {
+			fmt.Println(quack.GetRandomDuck(quack.MoodHappy))
+			fmt.Println(icon("✅") + "Audit passed! Your environment is well organized.")
 		}
 	} else {
 		if hasErrors {
-			fmt.Println("❌ Audit found issues that need attention!")
+			fmt.Println(icon("❌") + "Audit found issues that need attention!")
 		} else {
-			fmt.Println("✅ Audit passed! Your environment is well organized.")
+			fmt.Println(icon("✅") + "Audit passed! Your environment is well organized.")
 		}
 	}
 
 	if hasErrors {
-		os.Exit(1)
+		return exitStatus(1)
 	}
 
 	return nil
 }
 
+// newCompareOptions builds comparison options from the global comparison flags
+func newCompareOptions() (*checker.CompareOptions, error) {
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := checker.DefaultCompareOptions()
+	opts.Parse = parseOpts
+
+	if useOSEnv {
+		opts.EnvLookup = os.LookupEnv
+	}
+
+	opts.Mode, err = selectedCompareMode()
+	if err != nil {
+		return nil, err
+	}
+	opts.ResolveRefs = resolveRefs
+	opts.ShowValues = showValues
+	opts.Expand = expandRefs
+	opts.DetectSecrets = detectSecrets
+	opts.CaseInsensitive = caseInsensitive
+	opts.RequireValues = requireValues
+	opts.SkipEmptyExample = skipEmptyExample
+
+	opts.Ignore, err = loadIgnorePatterns()
+	if err != nil {
+		return nil, err
+	}
+	opts.EmptyAsMissing = emptyAsMissing
+
+	order := findingOrder
+	if noSort && !rootCmd.PersistentFlags().Changed("order") {
+		order = "file"
+	}
+	switch order {
+	case "alpha":
+	case "file":
+		opts.FileOrder = true
+	default:
+		return nil, fmt.Errorf("invalid --order %q (use alpha or file)", findingOrder)
+	}
+
+	// Key transforms: a built-in transform followed by explicit renames
+	var transforms []checker.KeyTransform
+	if transformName != "" {
+		transform, err := checker.LookupTransform(transformName)
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, transform)
+	}
+	if transformMap != "" {
+		transform, err := checker.LoadKeyMap(transformMap)
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, transform)
+	}
+
+	if len(transforms) > 0 {
+		transform := checker.ChainTransforms(transforms...)
+		switch transformSide {
+		case "both":
+			opts.EnvTransform, opts.ExampleTransform = transform, transform
+		case "env":
+			opts.EnvTransform = transform
+		case "example":
+			opts.ExampleTransform = transform
+		default:
+			return nil, fmt.Errorf("invalid --transform-side %q (use both, env or example)", transformSide)
+		}
+	}
+
+	return opts, nil
+}
+
+// parseCache is the parse cache of the current run, shared with the checkers
+var parseCache *parser.Cache
+
+// useParseCache starts a fresh parse cache for the CLI and the checkers
+func useParseCache() {
+	parseCache = parser.NewCache()
+	checker.UseParseCache(parseCache)
+}
+
+// newParseOptions builds parse options from the global parsing flags
+func newParseOptions() (*parser.ParseOptions, error) {
+	encoding, err := parser.LookupEncoding(envEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --encoding: %w", err)
+	}
+
+	opts := &parser.ParseOptions{
+		INI:      iniMode,
+		Encoding: encoding,
+	}
+
+	switch envFormat {
+	case "dotenv":
+	case "docker":
+		opts.Docker = true
+	default:
+		return nil, fmt.Errorf("unknown --env-format %q (use dotenv or docker)", envFormat)
+	}
+
+	return opts, nil
+}
+
+// compareEnvFiles compares the env file against the example using the global flags
+func compareEnvFiles() (*checker.DiffResult, error) {
+	opts, err := newCompareOptions()
+	if err != nil {
+		return nil, err
+	}
+	return checker.CompareEnvFiles(envFile, exampleFile, opts)
+}
+
+// defaultIgnoreFile lists key patterns to ignore, read from the --root directory
+const defaultIgnoreFile = ".quackignore"
+
+// loadIgnorePatterns returns the patterns of .quackignore, when present,
+// followed by those of --ignore
+func loadIgnorePatterns() ([]string, error) {
+	patterns := []string{}
+	if filename := rootPath(defaultIgnoreFile); fileExists(filename) {
+		fromFile, err := parser.ParseQuackignore(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", defaultIgnoreFile, err)
+		}
+		patterns = append(patterns, fromFile...)
+	}
+
+	for _, pattern := range ignorePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --ignore pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// duckFileEnv names the duck file when --duck-file is not given
+const duckFileEnv = "ENVQUACK_DUCK_FILE"
+
+// duckSeparator is the line between the happy and the angry duck of a duck file
+const duckSeparator = "%%"
+
+// loadDuckFile replaces the built-in ducks with the art of --duck-file: one
+// duck for every header, or a happy and an angry one separated by a %% line.
+// A missing or empty file keeps the built-in ducks.
+func loadDuckFile() error {
+	filename := duckFile
+	if filename == "" {
+		filename = os.Getenv(duckFileEnv)
+	}
+	if filename == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read duck file: %w", err)
+	}
+
+	happy, angry := string(data), ""
+	lines := strings.Split(strings.ReplaceAll(happy, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == duckSeparator {
+			happy = strings.Join(lines[:i], "\n")
+			angry = strings.Join(lines[i+1:], "\n")
+			break
+		}
+	}
+
+	happy = strings.Trim(happy, "\r\n")
+	angry = strings.Trim(angry, "\r\n")
+	if angry == "" {
+		angry = happy
+	}
+	if strings.TrimSpace(happy) == "" {
+		happy = ""
+	}
+	if strings.TrimSpace(angry) == "" {
+		angry = ""
+	}
+	quack.SetCustomDuck(happy, angry)
+	return nil
+}
+
+// newReportOptions builds report options from the global output flags
+func newReportOptions(showDuck, verbose bool) *checker.ReportOptions {
+	return &checker.ReportOptions{
+		ShowDuck:  showDuck,
+		Colorize:  !noColor,
+		Emoji:     !noEmoji,
+		Verbose:   verbose,
+		Plain:     plain,
+		MaxIssues: maxIssues,
+		Policy:    newExitPolicy(),
+	}
+}
+
+// selectedCompareMode returns the --compare-mode, which --compare-values and
+// --resolve-before-compare turn to both unless it is set
+func selectedCompareMode() (checker.CompareMode, error) {
+	mode, err := checker.ParseCompareMode(compareMode)
+	if err != nil {
+		return "", err
+	}
+	modeSet := rootCmd.PersistentFlags().Changed("compare-mode")
+	if !modeSet && (compareValues || resolveRefs) {
+		mode = checker.CompareBoth
+	}
+	return mode, nil
+}
+
+// newExitPolicy builds the exit policy from the global flags
+func newExitPolicy() *checker.ExitPolicy {
+	policy := checker.DefaultExitPolicy()
+	policy.ShowExtra = showExtra
+	policy.AllowExtra = allowExtra
+	policy.FailOn = failOn
+	return policy
+}
+
+// icon returns an emoji prefix for status lines, or nothing when emoji are disabled
+func icon(emoji string) string {
+	if noEmoji {
+		return ""
+	}
+	return emoji + " "
+}
+
+// applyRootDir resolves the relative file flags against --root
+func applyRootDir() error {
+	if rootDir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(rootDir)
+	if err != nil {
+		return fmt.Errorf("root directory error: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("root %s is not a directory", rootDir)
+	}
+
+	for _, path := range []*string{&envFile, &exampleFile, &composeFile, &dockerfileFile, &transformMap, &packageJSON, &makefile, &openAPIFile, &secretsDir, &schemaFile} {
+		*path = rootPath(*path)
+	}
+	for i := range envPatterns {
+		envPatterns[i] = rootPath(envPatterns[i])
+	}
+	for i := range k8sFiles {
+		k8sFiles[i] = rootPath(k8sFiles[i])
+	}
+	for i := range helmFiles {
+		helmFiles[i] = rootPath(helmFiles[i])
+	}
+
+	return nil
+}
+
+// rootPath resolves a relative path against --root; absolute paths, paths
+// already under --root and git sources are unchanged, so a path is never
+// rooted twice
+func rootPath(path string) string {
+	if rootDir == "" || path == "" || filepath.IsAbs(path) || parser.IsGitSource(path) || parser.IsRemoteSource(path) {
+		return path
+	}
+	if rel, err := filepath.Rel(rootDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(rootDir, path)
+}
+
+// normalizeFlagName maps flag aliases onto their canonical names
+func normalizeFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "professional":
+		name = "plain"
+	case "strict":
+		name = "show-extra"
+	}
+	return pflag.NormalizedName(name)
+}
+
+// runAuditStructured runs every audit check and renders the combined findings
+// in a structured format
+func runAuditStructured() error {
+	if outputFormat != "csv" && outputFormat != "table" {
+		return fmt.Errorf("unsupported format %q for audit (use text, csv or table)", outputFormat)
+	}
+
+	findings, hasIssues, err := collectAuditFindings()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "table" {
+		fmt.Print(checker.GenerateTableReport(findings, newReportOptions(false, verbose)))
+	} else {
+		report, err := checker.GenerateCSVReport(findings)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	}
+
+	if hasIssues {
+		return exitStatus(1)
+	}
+
+	return nil
+}
+
+// compareCompose runs the compose comparison with the audit options applied
+func compareCompose(envFiles []string) (*checker.ComposeDiffResult, error) {
+	composeOpts := checker.DefaultComposeOptions()
+	composeOpts.OnlyServices = onlyServices
+
+	result, err := checker.CompareComposeWithEnv(composeFile, envFiles, composeOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A mistyped service would otherwise leave it silently unchecked
+	if len(result.UnknownServices) > 0 {
+		return nil, fmt.Errorf("--only-services patterns matched no service: %s", strings.Join(result.UnknownServices, ", "))
+	}
+
+	if checkGitignore {
+		if err := checker.CheckGitignoreCoverage(result, composeFile, rootPath(".gitignore")); err != nil {
+			return nil, err
+		}
+	}
+
+	if checkDockerignore {
+		if err := checker.CheckDockerignoreCoverage(result, composeFile, rootPath(".dockerignore")); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// collectAuditFindings gathers normalized findings from every available source
+// and reports whether any source has issues
+func collectAuditFindings() ([]checker.Finding, bool, error) {
+	findings := []checker.Finding{}
+	hasIssues := false
+
+	envFiles := []string{}
+	if fileExists(envFile) {
+		envFiles = append(envFiles, envFile)
+	}
+
+	if fileExists(exampleFile) && fileExists(envFile) {
+		result, err := compareEnvFiles()
+		if err != nil {
+			return nil, false, fmt.Errorf("env check failed: %w", err)
+		}
+		findings = append(findings, result.VisibleFindings(newExitPolicy())...)
+		hasIssues = hasIssues || !checker.DecideExit(result, newExitPolicy()).OK()
+	}
+
+	if fileExists(composeFile) {
+		result, err := compareCompose(envFiles)
+		if err != nil {
+			return nil, false, fmt.Errorf("compose check failed: %w", err)
+		}
+		findings = append(findings, result.Findings()...)
+		hasIssues = hasIssues || result.HasIssues()
+	}
+
+	if fileExists(dockerfileFile) {
+		result, err := checker.CompareDockerfileWithEnv(dockerfileFile, envFiles)
+		if err != nil {
+			return nil, false, fmt.Errorf("dockerfile check failed: %w", err)
+		}
+		findings = append(findings, result.Findings()...)
+		hasIssues = hasIssues || result.HasIssues()
+	}
+
+	return findings, hasIssues, nil
+}
+
 func checkFileExists(filename string) error {
+	// Files at a git ref or URL are checked when they are read
+	if parser.IsGitSource(filename) || parser.IsRemoteSource(filename) {
+		return nil
+	}
+
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		return fmt.Errorf("file %s does not exist", filename)
 	}
@@ -314,6 +1363,10 @@ func checkFileExists(filename string) error {
 
 // fileExists is a helper that returns true if file exists, false otherwise
 func fileExists(filename string) bool {
+	if parser.IsGitSource(filename) || parser.IsRemoteSource(filename) {
+		return true
+	}
+
 	_, err := os.Stat(filename)
 	return err == nil
 }
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is read from the working directory when --config is not given
+const defaultConfigFile = ".envquack.yaml"
+
+// configEntry is one setting of the config file
+type configEntry struct {
+	Key    string   // Flag name, with underscores mapped to dashes
+	Line   int      // 1-based line of the key
+	Values []string // One value, or the items of a list
+}
+
+// configProblem is a validation error at a line of the config file
+type configProblem struct {
+	Line    int
+	Message string
+}
+
+// configValidators check the values of settings beyond their type
+var configValidators = map[string]func(value string) error{
+	"scan-refs": func(value string) error {
+		_, err := regexp.Compile(value)
+		return err
+	},
+	"only-services": func(value string) error {
+		_, err := path.Match(strings.TrimPrefix(value, "!"), "")
+		return err
+	},
+	"ignore": func(value string) error {
+		_, err := path.Match(value, "")
+		return err
+	},
+	"compare-mode": func(value string) error {
+		_, err := checker.ParseCompareMode(value)
+		return err
+	},
+	"transform": func(value string) error {
+		_, err := checker.LookupTransform(value)
+		return err
+	},
+	"transform-side": oneOf("both", "env", "example"),
+	"env-format":     oneOf("dotenv", "docker"),
+	"encoding": func(value string) error {
+		_, err := parser.LookupEncoding(value)
+		return err
+	},
+	"fail-on":   oneOf(checker.FailOnCategories...),
+	"inherit":   oneOf(parser.InheritGitRoot, parser.InheritFSRoot),
+	"order":     oneOf("alpha", "file"),
+	"helm-keys": oneOf(checker.HelmKeysEnv, checker.HelmKeysDotted),
+	"format":    oneOf("text", "json", "csv", "table", "env", "env-extra", "prometheus", "slack", "checkstyle", "github"),
+}
+
+// oneOf returns a validator accepting only the given values
+func oneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+// configPath returns the config file to read and whether it was asked for explicitly
+func configPath() (string, bool) {
+	if configFile != "" {
+		return configFile, true
+	}
+	return rootPath(defaultConfigFile), false
+}
+
+// loadConfig reads the settings of a config file. The file is a flat YAML
+// mapping of flag names (allow_extra or allow-extra) to values or lists.
+func loadConfig(filename string) ([]configEntry, []configProblem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	// An empty file has no settings
+	if len(root.Content) == 0 {
+		return nil, nil, nil
+	}
+
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, []configProblem{{doc.Line, "expected a mapping of settings"}}, nil
+	}
+
+	entries := []configEntry{}
+	problems := []configProblem{}
+	seen := make(map[string]int)
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		keyNode, valueNode := doc.Content[i], doc.Content[i+1]
+		key := strings.ReplaceAll(keyNode.Value, "_", "-")
+
+		if line, dup := seen[key]; dup {
+			problems = append(problems, configProblem{keyNode.Line, fmt.Sprintf("%s is already set on line %d", keyNode.Value, line)})
+			continue
+		}
+		seen[key] = keyNode.Line
+
+		entry := configEntry{Key: key, Line: keyNode.Line}
+		switch valueNode.Kind {
+		case yaml.ScalarNode:
+			entry.Values = []string{valueNode.Value}
+		case yaml.SequenceNode:
+			for _, item := range valueNode.Content {
+				if item.Kind != yaml.ScalarNode {
+					problems = append(problems, configProblem{item.Line, fmt.Sprintf("%s: list items must be plain values", keyNode.Value)})
+					continue
+				}
+				entry.Values = append(entry.Values, item.Value)
+			}
+		default:
+			problems = append(problems, configProblem{valueNode.Line, fmt.Sprintf("%s: expected a value or a list", keyNode.Value)})
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, problems, nil
+}
+
+// validateConfig checks every entry against the flags of all commands under
+// root: unknown keys, values of the wrong type and invalid patterns
+func validateConfig(root *cobra.Command, entries []configEntry) []configProblem {
+	problems := []configProblem{}
+	flags := knownFlags(root)
+
+	for _, entry := range entries {
+		flag, exists := flags[entry.Key]
+		if !exists || entry.Key == "config" || entry.Key == "help" {
+			message := fmt.Sprintf("unknown setting %q", entry.Key)
+			if suggestion := closestFlag(entry.Key, flags); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			problems = append(problems, configProblem{entry.Line, message})
+			continue
+		}
+
+		kind := flag.Value.Type()
+		if len(entry.Values) != 1 && !strings.HasSuffix(kind, "Slice") {
+			problems = append(problems, configProblem{entry.Line, fmt.Sprintf("%s takes a single value, not a list", entry.Key)})
+			continue
+		}
+
+		for _, value := range entry.Values {
+			if err := checkConfigValue(kind, value); err != nil {
+				problems = append(problems, configProblem{entry.Line, fmt.Sprintf("%s: %v", entry.Key, err)})
+				continue
+			}
+			if validate := configValidators[entry.Key]; validate != nil {
+				if err := validate(value); err != nil {
+					problems = append(problems, configProblem{entry.Line, fmt.Sprintf("%s: invalid value %q: %v", entry.Key, value, err)})
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// checkConfigValue checks that value parses as the given flag type
+func checkConfigValue(kind, value string) error {
+	switch kind {
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean (use true or false)", value)
+		}
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case "duration":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%q is not a duration (e.g. 30s)", value)
+		}
+	}
+	return nil
+}
+
+// knownFlags returns every flag of every command under root, by name
+func knownFlags(root *cobra.Command) map[string]*pflag.Flag {
+	flags := make(map[string]*pflag.Flag)
+	add := func(f *pflag.Flag) { flags[f.Name] = f }
+
+	root.PersistentFlags().VisitAll(add)
+	for _, cmd := range root.Commands() {
+		cmd.Flags().VisitAll(add)
+	}
+	return flags
+}
+
+// closestFlag suggests the flag name nearest to a misspelled key
+func closestFlag(key string, flags map[string]*pflag.Flag) string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// formatConfigProblems renders problems as file:line: message, one per line
+func formatConfigProblems(filename string, problems []configProblem) string {
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		lines = append(lines, fmt.Sprintf("%s:%d: %s", filename, p.Line, p.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyConfig validates the config file and uses its settings as defaults for
+// the flags of cmd that were not given on the command line. A missing default
+// config file is fine; any invalid setting stops the run.
+func applyConfig(cmd *cobra.Command) error {
+	filename, explicit := configPath()
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+
+	entries, problems, err := loadConfig(filename)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, validateConfig(cmd.Root(), entries)...)
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config file (run envquack self-check for details):\n%s", formatConfigProblems(filename, problems))
+	}
+
+	for _, entry := range entries {
+		// Set through the flag set, so the flag counts as given
+		flag := cmd.Flags().Lookup(entry.Key)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(entry.Key, strings.Join(entry.Values, ",")); err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", filename, entry.Line, entry.Key, err)
+		}
+	}
+
+	return nil
+}
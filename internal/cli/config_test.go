@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestConfigCountsAsGivenFlags(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "A=expected\nB=\n")
+	differing := writeFile(t, dir, "differing.env", "A=actual\nB=1\n")
+	extra := writeFile(t, dir, "extra.env", "A=expected\nB=1\nC=1\n")
+
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		want   int
+	}{
+		{"fail-on is parsed", "fail-on: [EXTRA]\n", []string{"--env", extra, "--show-extra"}, 1},
+		{"fail-on tolerates", "fail-on: [missing]\n", []string{"--env", extra, "--show-extra"}, 0},
+		{"fail-on given wins", "fail-on: [missing]\n", []string{"--env", extra, "--show-extra", "--fail-on", "extra"}, 1},
+		{"compare-mode keeps keys", "compare-mode: keys\n", []string{"--env", differing, "--compare-values"}, 0},
+		{"compare-mode given wins", "compare-mode: keys\n", []string{"--env", differing, "--compare-mode", "both"}, 1},
+		{"without compare-mode", "", []string{"--env", differing, "--compare-values"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := writeFile(t, t.TempDir(), ".envquack.yaml", tt.config)
+			args := append([]string{"check", "--example", example, "--config", config}, tt.args...)
+			if got := exitCode(run(t, args...)); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	config := writeFile(t, t.TempDir(), ".envquack.yaml", "fail-on: [sometimes]\n")
+	err := run(t, "check", "--example", example, "--env", extra, "--config", config)
+	if err == nil || isExitError(err) {
+		t.Errorf("invalid fail-on in config = %v, want an error", err)
+	}
+}
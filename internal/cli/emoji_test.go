@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNoEmojiOutputIsASCII(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "# @required\nREQUIRED=\nMISSING=\nPORT=8080\n# @deprecated use PORT\nOLD_PORT=\n# OPTIONAL=\n")
+	env := writeFile(t, dir, ".env", "REQUIRED=\nPORT=9090\nOLD_PORT=1\nEXTRA=1\nEXTRA=2\nref=${UNSET}\nOPTIONAL=x\n")
+	aligned := writeFile(t, dir, "aligned.env", "REQUIRED=x\nMISSING=x\nPORT=8080\n")
+	compose := writeFile(t, dir, "docker-compose.yml", "services:\n  app:\n    image: app\n    environment:\n      - DB=${DB_URL}\n  idle:\n    image: idle\n")
+	dockerfile := writeFile(t, dir, "Dockerfile", "FROM scratch\nARG UNUSED\nENV HARDCODED=1\nENV NEEDED=${NEEDED}\n")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"check with findings", []string{"check", "--env", env, "--show-extra", "--compare-values", "--expand", "--secrets"}},
+		{"check with duck", []string{"check", "--env", env, "--compare-values"}},
+		{"check aligned", []string{"check", "--env", aligned}},
+		{"check table", []string{"check", "--env", env, "--format", "table"}},
+		{"lint", []string{"lint", "--env", env}},
+		{"audit", []string{"audit", "--env", env, "--compose", compose, "--dockerfile", dockerfile}},
+		{"stats", []string{"stats", "--env", env}},
+		{"list", []string{"list", "--env", env, "--compose", compose, "--dockerfile", dockerfile}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append(tt.args, "--example", example, "--no-emoji")
+			out, err := capture(t, args...)
+			if exitCode(err) > 1 || (err != nil && !isExitError(err)) {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+			if out == "" {
+				t.Fatal("no output")
+			}
+			for i, line := range strings.Split(out, "\n") {
+				for _, r := range line {
+					if r > 127 {
+						t.Errorf("line %d has %q: %s", i+1, r, line)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+// isExitError reports whether err only carries an exit status
+func isExitError(err error) bool {
+	var exit *ExitError
+	return errors.As(err, &exit)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+)
+
+// defaultEnvFile is read when --env is not given
+const defaultEnvFile = ".env"
+
+// envPatterns holds every --env value in order; envFile is the first, the
+// file that commands reading a single env file use
+var envPatterns = []string{defaultEnvFile}
+
+// envFlag is the value of --env. It may be repeated, and check merges the
+// files (see multipleEnvFiles).
+type envFlag struct {
+	set bool // Whether the default was replaced yet
+}
+
+func (f *envFlag) String() string {
+	return strings.Join(envPatterns, ",")
+}
+
+func (f *envFlag) Set(value string) error {
+	if !f.set {
+		envPatterns = nil
+		f.set = true
+	}
+	envPatterns = append(envPatterns, value)
+	envFile = envPatterns[0]
+	return nil
+}
+
+func (f *envFlag) Type() string {
+	return "string"
+}
+
+// multipleEnvFiles reports whether --env was repeated or is a glob, so check
+// merges several env files instead of reading one
+func multipleEnvFiles() bool {
+	return len(envPatterns) > 1 || strings.ContainsAny(envFile, "*?[")
+}
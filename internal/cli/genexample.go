@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fromCode     bool
+	mergeExample bool
+)
+
+// genExampleCmd represents the gen-example command
+var genExampleCmd = &cobra.Command{
+	Use:   "gen-example [dir]",
+	Short: "Generate .env.example from the variables your code reads",
+	Long: `Gen-example writes .env.example (or --example) from the variables your code
+reads. With --from-code, the Go files under dir (default: the current
+directory) are scanned for os.Getenv and os.LookupEnv calls; each variable is
+written once with a TODO comment and the places it is used.
+
+An existing example is never overwritten. Use --merge to append only the
+variables it doesn't have yet, leaving everything else untouched.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGenExample,
+}
+
+func init() {
+	genExampleCmd.Flags().BoolVar(&fromCode, "from-code", false, "scan Go source for os.Getenv/os.LookupEnv calls")
+	genExampleCmd.Flags().BoolVar(&mergeExample, "merge", false, "add only newly discovered variables to an existing example")
+	rootCmd.AddCommand(genExampleCmd)
+}
+
+// genExampleSeparator marks the block of variables appended by gen-example --merge
+const genExampleSeparator = "# Added by envquack gen-example"
+
+func runGenExample(cmd *cobra.Command, args []string) error {
+	if !fromCode {
+		return fmt.Errorf("gen-example needs a source of variables; use --from-code")
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	dir = rootPath(dir)
+
+	usages, err := parser.ScanGoEnvUsages(dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	// Group usages by variable, keeping where each one is read
+	locations := make(map[string][]string)
+	keys := []string{}
+	for _, usage := range usages {
+		if _, seen := locations[usage.Key]; !seen {
+			keys = append(keys, usage.Key)
+		}
+		locations[usage.Key] = append(locations[usage.Key], fmt.Sprintf("%s:%d", usage.File, usage.Line))
+	}
+	sort.Strings(keys)
+
+	exists := fileExists(exampleFile)
+	if exists && !mergeExample {
+		return fmt.Errorf("%s already exists; use --merge to add only new variables", exampleFile)
+	}
+
+	// Only variables the example doesn't document yet
+	if exists {
+		existing, err := parser.ParseEnvFile(exampleFile)
+		if err != nil {
+			return fmt.Errorf("failed to parse example file: %w", err)
+		}
+
+		newKeys := []string{}
+		for _, key := range keys {
+			if !existing.Has(key) {
+				newKeys = append(newKeys, key)
+			}
+		}
+		keys = newKeys
+	}
+
+	if len(keys) == 0 {
+		fmt.Println(icon("✅") + "No new variables found in code.")
+		return nil
+	}
+
+	var block strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			block.WriteString("\n")
+		}
+		doc := []string{
+			"TODO: describe " + key,
+			"Used in: " + strings.Join(locations[key], ", "),
+		}
+		block.WriteString(parser.FormatDocumentedEntry(key, "", doc))
+	}
+
+	if exists {
+		err = appendBlock(exampleFile, genExampleSeparator, block.String())
+	} else {
+		err = os.WriteFile(exampleFile, []byte(block.String()), 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", exampleFile, err)
+	}
+
+	fmt.Printf("%sWrote %d variables to %s:\n", icon("📝"), len(keys), exampleFile)
+	for _, key := range keys {
+		fmt.Printf("  + %s\n", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initForce       bool
+	initPlaceholder string
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold .env.example from an existing .env",
+	Long: `Init writes .env.example (or --example) from the keys of .env (or --env),
+in the order .env declares them, with every value blanked out or replaced by
+--placeholder. Comments directly above a key are kept as its documentation.
+
+An existing example is never overwritten unless --force is given.`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite an existing example file")
+	initCmd.Flags().StringVar(&initPlaceholder, "placeholder", "", "value written for every key instead of leaving it empty, e.g. <changeme>")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if err := checkFileExists(envFile); err != nil {
+		return fmt.Errorf("env file error: %w", err)
+	}
+	if fileExists(exampleFile) && !initForce {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", exampleFile)
+	}
+
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+	parsed, err := parser.ParseEnvFileWithOptions(envFile, parseOpts)
+	if err != nil {
+		return fmt.Errorf("failed to parse env file: %w", err)
+	}
+
+	// The first assignment of a key carries its documentation
+	docs := make(map[string][]string)
+	for _, entry := range parsed.Entries {
+		if _, seen := docs[entry.Key]; !seen {
+			docs[entry.Key] = entry.Doc
+		}
+	}
+
+	keys := parsed.Keys()
+	var content strings.Builder
+	for i, key := range keys {
+		if i > 0 && len(docs[key]) > 0 {
+			content.WriteString("\n")
+		}
+		content.WriteString(parser.FormatDocumentedEntry(key, initPlaceholder, docs[key]))
+	}
+
+	if err := os.WriteFile(exampleFile, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exampleFile, err)
+	}
+
+	if !noDuck && !plain {
+		fmt.Println(quack.GetInitMessage())
+	}
+	fmt.Printf("%sWrote %d variables from %s to %s\n", icon("📝"), len(keys), envFile, exampleFile)
+	return nil
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+)
+
+// interactiveSeparator marks the block of variables appended by --interactive
+const interactiveSeparator = "# Added by envquack check --interactive"
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// runInteractiveFix prompts for a value for each missing variable, showing the
+// example's documentation as a hint, and appends the answers to the env file.
+// An empty answer skips the variable.
+func runInteractiveFix(result *checker.DiffResult) error {
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("--interactive requires a terminal on stdin")
+	}
+
+	if len(result.Missing) == 0 {
+		return nil
+	}
+
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+	example, err := parser.ParseEnvFileWithOptions(exampleFile, parseOpts)
+	if err != nil {
+		return fmt.Errorf("failed to parse example file: %w", err)
+	}
+
+	fmt.Printf("%d variables are missing from %s. Enter a value, or leave empty to skip.\n\n", len(result.Missing), envFile)
+
+	// Ask in example order so related variables stay together
+	missing := make(map[string]bool, len(result.Missing))
+	for _, key := range result.Missing {
+		missing[key] = true
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	values := make(parser.EnvVars)
+	var entered []string
+
+	for _, entry := range example.Entries {
+		key := entry.Key
+		if !missing[key] {
+			continue
+		}
+		delete(missing, key)
+
+		for _, line := range entry.Doc {
+			fmt.Printf("  # %s\n", line)
+		}
+		if entry.Value != "" {
+			fmt.Printf("  (example: %s)\n", entry.Value)
+		}
+
+		fmt.Printf("%s=", key)
+		answer, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if value := strings.TrimSpace(answer); value != "" {
+			values[key] = value
+			entered = append(entered, key)
+		}
+
+		// Stdin closed, skip everything that's left
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			break
+		}
+		fmt.Println()
+	}
+
+	if err := appendVars(envFile, interactiveSeparator, entered, values); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sWrote %d variables to %s, skipped %d.\n\n", icon("✅"), len(entered), envFile, len(result.Missing)-len(entered))
+	return nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check .env formatting and hygiene",
+	Long: `Lint inspects your .env file for style and hygiene problems.
+
+Rules:
+- inconsistent-quoting: values quoted differently from similar values in the file
+- naming-convention: values that do not match their key name, e.g. a non-numeric *_PORT
+- numeric-pitfall: integers with a leading zero (PORT=08080) or beyond the int64 range
+- todo-marker: comments with TODO, FIXME, XXX or HACK (see --todo-markers),
+  counted as informational findings and listed with --verbose
+
+Exits non-zero only for findings of warning severity or above.`,
+	RunE: runLint,
+}
+
+var todoMarkers []string
+
+func init() {
+	lintCmd.Flags().StringSliceVar(&todoMarkers, "todo-markers", checker.DefaultTodoMarkers, "comment markers reported as open action items (empty to disable)")
+
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if err := checkFileExists(envFile); err != nil {
+		return fmt.Errorf("env file error: %w", err)
+	}
+
+	result, err := checker.LintEnvFile(envFile, &checker.LintOptions{TodoMarkers: todoMarkers})
+	if err != nil {
+		return fmt.Errorf("failed to lint env file: %w", err)
+	}
+
+	opts := newReportOptions(!noDuck, verbose)
+	fmt.Print(checker.GenerateLintReport(result, opts))
+
+	if result.HasSeverity(checker.SeverityWarning) {
+		return exitStatus(1)
+	}
+
+	return nil
+}
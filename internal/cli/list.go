@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every variable across .env, .env.example, compose and Dockerfile",
+	Long: `List prints the union of all variables found in .env, .env.example,
+docker-compose.yml and the Dockerfile, with a column per source showing which
+ones define or reference each variable. Missing files are skipped.
+
+Use --format json for a machine-readable inventory.`,
+	RunE: runList,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	sources := checker.InventorySources{}
+	if fileExists(envFile) {
+		sources.Env = envFile
+	}
+	if fileExists(exampleFile) {
+		sources.Example = exampleFile
+	}
+	if fileExists(composeFile) {
+		sources.Compose = composeFile
+	}
+	if fileExists(dockerfileFile) {
+		sources.Dockerfile = dockerfileFile
+	}
+
+	inventory, err := checker.CollectInventory(sources)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "text":
+		fmt.Print(checker.GenerateInventoryReport(inventory, newReportOptions(false, verbose)))
+	case "json":
+		report, err := checker.GenerateInventoryJSON(inventory)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	default:
+		return fmt.Errorf("unsupported format %q for list (use text or json)", outputFormat)
+	}
+
+	return nil
+}
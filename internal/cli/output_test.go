@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportsHonourOutput(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "A=\nB=\n")
+	first := writeFile(t, dir, "first.env", "A=1\n")
+	second := writeFile(t, dir, "second.env", "A=1\nB=2\n")
+	source := writeFile(t, dir, "main.go", "os.Getenv(\"A\")\nos.Getenv(\"UNDOCUMENTED\")\n")
+	pkg := writeFile(t, dir, "package.json", `{"scripts": {"start": "node . --port $PORT_UNDOCUMENTED"}}`)
+	mk := writeFile(t, dir, "Makefile", "deploy:\n\tkubectl --context $(KUBE_CONTEXT) apply\n")
+	openapi := writeFile(t, dir, "openapi.yaml", "openapi: 3.0.0\nservers:\n  - url: https://{host}/\n")
+	scanRefs := []string{"check", source, "--scan-refs", `Getenv\("(\w+)"\)`}
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want string // Expected in the report file
+	}{
+		{"several files as text", []string{"check", first, second, "--format", "text"}, 1, "Checked 2 files: 1 clean, 1 with issues"},
+		{"several files as json", []string{"check", first, second, "--format", "json"}, 1, `"with_issues": 1`},
+		{"several files as prometheus", []string{"check", first, second, "--format", "prometheus"}, 1, "envquack_missing_variables 1"},
+		{"several files as github", []string{"check", first, second, "--format", "github"}, 1, "::error file=" + example + ",line=2,title=Missing env var::B is required by " + example},
+		{"scan-refs as text", append(scanRefs, "--format", "text"), 1, "UNDOCUMENTED"},
+		{"scan-refs as json", append(scanRefs, "--format", "json"), 1, `"exit_reason": "missing_required"`},
+		{"scan-refs as csv", append(scanRefs, "--format", "csv"), 1, "refs,undocumented,UNDOCUMENTED,error,"},
+		{"package.json as github", []string{"check", "--package-json", pkg, "--format", "github"}, 1, "::error file=" + pkg + ",title=Env var missing in example::PORT_UNDOCUMENTED is used by package.json scripts"},
+		{"Makefile as table", []string{"check", "--makefile", mk, "--format", "table"}, 1, "KUBE_CONTEXT"},
+		{"Makefile tolerated", []string{"check", "--makefile", mk, "--format", "csv", "--fail-on", "invalid"}, 0, "makefile,undocumented,KUBE_CONTEXT,error,"},
+		{"OpenAPI as json", []string{"check", "--openapi", openapi, "--format", "json"}, 1, `"source": "openapi"`},
+		{"scan-refs tolerated", append(scanRefs, "--format", "json", "--fail-on", "changed"), 0, `"exit_code": 0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "report")
+			args := append(tt.args, "--example", example, "--output", output)
+			stdout, err := capture(t, args...)
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("exit code = %d (%v), want %d", code, err, tt.code)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want the report in --output only", stdout)
+			}
+
+			report, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(report), tt.want) {
+				t.Errorf("report =\n%s\nwant it to contain %q", report, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffAgainstPreviousNeedsText(t *testing.T) {
+	dir := t.TempDir()
+	example := writeFile(t, dir, ".env.example", "A=\n")
+	env := writeFile(t, dir, ".env", "A=1\n")
+	state := filepath.Join(dir, "state.json")
+
+	for _, format := range []string{"json", "csv", "github"} {
+		err := run(t, "check", "--example", example, "--env", env, "--diff-against-previous="+state, "--format", format)
+		if err == nil || isExitError(err) {
+			t.Errorf("--format %s = %v, want an error", format, err)
+		}
+	}
+	if err := run(t, "check", "--example", example, env, env, "--diff-against-previous="+state); err == nil || isExitError(err) {
+		t.Errorf("several env files = %v, want an error", err)
+	}
+	if _, err := os.Stat(state); !os.IsNotExist(err) {
+		t.Errorf("rejected runs wrote %s", state)
+	}
+
+	if err := run(t, "check", "--example", example, "--env", env, "--diff-against-previous="+state); err != nil {
+		t.Fatalf("text report = %v", err)
+	}
+	if _, err := os.Stat(state); err != nil {
+		t.Errorf("text report did not save the state: %v", err)
+	}
+}
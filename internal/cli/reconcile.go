@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var reconcileFix bool
+
+// reconcileCmd represents the reconcile command
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile [dir]",
+	Short: "Reconcile .env.example with the variables code, compose and the Dockerfile use",
+	Long: `Reconcile compares what the project really uses with what .env.example
+documents. The Go files under dir (default: the current directory) are
+scanned for os.Getenv and os.LookupEnv calls, and docker-compose.yml and the
+Dockerfile for the variables they use; missing files are skipped.
+
+Each variable gets a recommended action:
+- keep: used and documented
+- add to example: used but undocumented
+- remove from example: documented but unused
+
+Exits non-zero when the example is out of sync. Use --fix to apply the
+actions: undocumented variables are appended with a TODO comment and where
+they are used, and unused ones are removed with their comments.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReconcile,
+}
+
+func init() {
+	reconcileCmd.Flags().BoolVar(&reconcileFix, "fix", false, "update the example: add undocumented variables and remove unused ones")
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+// reconcileSeparator marks the block of variables appended by reconcile --fix
+const reconcileSeparator = "# Added by envquack reconcile"
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unsupported format %q for reconcile (use text or json)", outputFormat)
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	sources := checker.ReconcileSources{CodeDir: rootPath(dir), Example: exampleFile}
+	if fileExists(composeFile) {
+		sources.Compose = composeFile
+	}
+	if fileExists(dockerfileFile) {
+		sources.Dockerfile = dockerfileFile
+	}
+	if !fileExists(exampleFile) {
+		sources.Example = ""
+	}
+
+	r, err := checker.Reconcile(sources)
+	if err != nil {
+		return err
+	}
+	r.Example = exampleFile
+
+	if outputFormat == "json" {
+		report, err := checker.GenerateReconcileJSON(r)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	} else {
+		fmt.Print(checker.GenerateReconcileReport(r, newReportOptions(false, verbose)))
+	}
+
+	if r.InSync() {
+		return nil
+	}
+
+	if !reconcileFix {
+		return exitStatus(1)
+	}
+
+	if err := applyReconciliation(r); err != nil {
+		return err
+	}
+	if outputFormat == "text" {
+		fmt.Printf("\n%sUpdated %s: added %d, removed %d\n", icon("📝"), exampleFile, len(r.Keys(checker.ActionAdd)), len(r.Keys(checker.ActionRemove)))
+	}
+
+	return nil
+}
+
+// applyReconciliation rewrites the example: unused variables are removed and
+// undocumented ones appended, documented like gen-example does
+func applyReconciliation(r *checker.Reconciliation) error {
+	existing, err := os.ReadFile(exampleFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", exampleFile, err)
+	}
+
+	content, err := parser.RemoveEntries(string(existing), r.Keys(checker.ActionRemove))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", exampleFile, err)
+	}
+
+	var block strings.Builder
+	for _, entry := range r.Variables {
+		if entry.Action != checker.ActionAdd {
+			continue
+		}
+		if block.Len() > 0 {
+			block.WriteString("\n")
+		}
+
+		doc := []string{"TODO: describe " + entry.Key}
+		if len(entry.CodeUsages) > 0 {
+			doc = append(doc, "Used in: "+strings.Join(entry.CodeUsages, ", "))
+		} else {
+			doc = append(doc, "Used in: "+strings.Join(entry.UsedIn, ", "))
+		}
+		block.WriteString(parser.FormatDocumentedEntry(entry.Key, "", doc))
+	}
+	if block.Len() > 0 {
+		content += appendedText([]byte(content), reconcileSeparator, block.String())
+	}
+
+	if err := os.WriteFile(exampleFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exampleFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// selfCheckCmd represents the self-check command
+var selfCheckCmd = &cobra.Command{
+	Use:   "self-check",
+	Short: "Validate EnvQuack's own config file",
+	Long: `Self-check validates .envquack.yaml (or the file given with --config).
+
+The config file is a flat mapping of flag names to values, used as defaults
+for flags not given on the command line:
+
+  example: config/.env.example
+  allow_extra: true
+  only_services: [web, "worker-*"]
+
+Every key must be a known flag, values must have the flag's type, and
+patterns (--scan-refs regexes, --only-services globs) must compile. Problems
+are reported with their line. Other commands refuse to run with an invalid
+config file.`,
+	RunE: runSelfCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(selfCheckCmd)
+}
+
+func runSelfCheck(cmd *cobra.Command, args []string) error {
+	filename, _ := configPath()
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("config file %s not found", filename)
+	}
+
+	entries, problems, err := loadConfig(filename)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, validateConfig(cmd.Root(), entries)...)
+
+	if len(problems) > 0 {
+		fmt.Println(formatConfigProblems(filename, problems))
+		fmt.Printf("\n%s%s has %d problem(s)\n", icon("❌"), filename, len(problems))
+		return exitStatus(1)
+	}
+
+	fmt.Printf("%s%s is valid (%d settings)\n", icon("✅"), filename, len(entries))
+	return nil
+}
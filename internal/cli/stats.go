@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show coverage statistics for .env against .env.example",
+	Long: `Stats summarizes how much of .env.example is covered by your .env file.
+
+It prints variable counts and a coverage bar colored by how complete your .env is.`,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if err := checkFileExists(exampleFile); err != nil {
+		return fmt.Errorf("example file error: %w", err)
+	}
+
+	if err := checkFileExists(envFile); err != nil {
+		return fmt.Errorf("env file error: %w", err)
+	}
+
+	result, err := compareEnvFiles()
+	if err != nil {
+		return fmt.Errorf("failed to compare files: %w", err)
+	}
+
+	opts := newReportOptions(false, verbose)
+	present := result.ExampleTotal - len(result.Missing)
+
+	fmt.Println(icon("📊") + "Environment statistics:")
+	fmt.Printf("  Example variables: %d\n", result.ExampleTotal)
+	fmt.Printf("  Present in .env:   %d\n", present)
+	fmt.Printf("  Missing:           %d\n", len(result.Missing))
+	fmt.Printf("  Extra:             %d\n", len(result.Extra))
+	fmt.Printf("  Coverage:          %s\n", checker.RenderCoverageBar(result.Coverage(), opts))
+
+	return nil
+}
@@ -0,0 +1,413 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/DuckDHD/EnvQuack/internal/parser"
+	"github.com/DuckDHD/EnvQuack/internal/quack"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync missing variables from .env.example to .env",
+	Long: `Sync adds missing variables from .env.example to your .env file with empty values.
+
+This helps you quickly scaffold your .env file based on the example.
+Finishes with a summary of example, already-present, added and still-missing
+counts; use --format json to get the summary as JSON.
+
+Use --with-values to copy the example's sample values (PORT=8080) instead.
+
+Use --patch to leave .env untouched and print the additions as a unified diff
+instead, for review or git apply. Use --dry-run to print the exact lines that
+would be appended without writing anything.
+
+Use --prune to also remove the variables .env sets but .env.example doesn't
+know, with the comments directly above them; keys matched by .quackignore or
+--ignore are kept. The original is backed up to .env.bak first, and you are
+asked to confirm unless --force is given.`,
+	RunE: runSync,
+}
+
+var (
+	syncPatch      bool
+	syncDryRun     bool
+	syncWithValues bool
+	syncPrune      bool
+	syncForce      bool
+)
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncPatch, "patch", false, "print the additions as a unified diff (for git apply) instead of writing .env")
+	syncCmd.Flags().BoolVar(&syncWithValues, "with-values", false, "copy each missing variable's sample value from the example instead of leaving it empty")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "print the lines that would be appended to .env without writing it")
+	syncCmd.Flags().BoolVar(&syncPrune, "prune", false, "remove variables that are not in the example from .env, after backing it up to .env.bak")
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "prune without asking for confirmation")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unsupported format %q for sync (use text or json)", outputFormat)
+	}
+	if syncPatch && outputFormat != "text" {
+		return fmt.Errorf("--patch prints a diff and can't be combined with --format %s", outputFormat)
+	}
+	if syncPatch && syncDryRun {
+		return fmt.Errorf("--patch already leaves .env untouched; use it or --dry-run")
+	}
+
+	// Progress messages are only shown for text output, and a patch is the
+	// only output
+	var out io.Writer = os.Stdout
+	if outputFormat == "json" || syncPatch {
+		out = io.Discard
+	}
+
+	// Check if example file exists
+	if err := checkFileExists(exampleFile); err != nil {
+		return fmt.Errorf("example file error: %w", err)
+	}
+
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+
+	// Parse example file
+	// Commented-out assignments document optional keys, which --prune keeps
+	exampleOpts := *parseOpts
+	exampleOpts.Docker = false
+	exampleOpts.CommentedKeys = true
+	exampleParsed, err := parseCache.ParseEnvFileWithOptions(exampleFile, &exampleOpts)
+	if err != nil {
+		return fmt.Errorf("failed to parse example file: %w", err)
+	}
+	example := exampleParsed.EnvVars()
+
+	// Parse existing env file (create if doesn't exist)
+	var env parser.EnvVars
+	if _, err := os.Stat(envFile); os.IsNotExist(err) {
+		env = make(parser.EnvVars)
+		if syncDryRun {
+			fmt.Fprintf(out, "Would create a new %s file.\n", envFile)
+		} else {
+			fmt.Fprintf(out, "Creating new %s file...\n", envFile)
+		}
+	} else {
+		parsed, err := parseCache.ParseEnvFileWithOptions(envFile, parseOpts)
+		if err != nil {
+			return fmt.Errorf("failed to parse env file: %w", err)
+		}
+		env = parsed.EnvVars()
+	}
+
+	// Find missing variables, never re-adding deprecated ones
+	result := checker.CompareEnvVars(env, example)
+	checker.ApplyDeprecations(result, env, exampleParsed)
+
+	// Added variables follow the example's layout, not the alphabet
+	checker.OrderByDeclaration(result, exampleParsed.Keys(), nil)
+
+	// Empty values unless the example's samples are wanted
+	var values parser.EnvVars
+	if syncWithValues {
+		values = example
+	}
+
+	// Extra variables to remove, never the ignored ones
+	var prune []string
+	if syncPrune {
+		patterns, err := loadIgnorePatterns()
+		if err != nil {
+			return err
+		}
+		prune = checker.PruneKeys(env, exampleParsed, patterns)
+	}
+
+	if syncPatch {
+		return printSyncPatch(envFile, result.Missing, values, prune)
+	}
+
+	summary := &checker.SyncSummary{
+		ExampleTotal:   result.ExampleTotal,
+		AlreadyPresent: countPresent(env, example),
+		Added:          result.Missing,
+		StillMissing:   []string{},
+		DryRun:         syncDryRun,
+	}
+
+	if syncPrune {
+		summary.Removed = []string{}
+		if syncDryRun {
+			summary.Removed = prune
+			printSyncPruneDryRun(out, envFile, prune)
+		} else if len(prune) > 0 {
+			removed, err := pruneEnvFile(out, envFile, prune)
+			if err != nil {
+				return err
+			}
+			if removed {
+				summary.Removed = prune
+			}
+		}
+	}
+
+	if syncDryRun && len(result.Missing) > 0 {
+		if err := printSyncDryRun(out, envFile, result.Missing, values); err != nil {
+			return err
+		}
+	} else if len(result.Missing) == 0 {
+		fmt.Fprintln(out, icon("✅")+"No missing variables to sync.")
+		if !noDuck && !plain {
+			fmt.Fprintln(out, "(Your gopher-duck is already happy!)")
+		}
+	} else {
+		// Show sync message
+		if !noDuck && !plain {
+			fmt.Fprintln(out, quack.GetRandomDuck(quack.MoodSyncing))
+		}
+		fmt.Fprintf(out, "Adding %d missing variables to %s:\n", len(result.Missing), envFile)
+
+		// Append missing variables to env file
+		if err := appendVars(envFile, syncSeparator, result.Missing, values); err != nil {
+			return err
+		}
+
+		for _, key := range result.Missing {
+			fmt.Fprintf(out, "  + %s\n", key)
+		}
+
+		// Re-check so a second run is guaranteed to be a no-op
+		summary.StillMissing, err = missingAfterSync(envFile, exampleParsed, parseOpts)
+		if err != nil {
+			return err
+		}
+
+		if len(summary.StillMissing) == 0 {
+			fmt.Fprintf(out, "\n%sSuccessfully synced %d variables!\n", icon("✅"), len(result.Missing))
+			fmt.Fprintln(out, "Don't forget to set the actual values in your .env file.")
+		}
+	}
+
+	// Final accounting
+	if outputFormat == "json" {
+		report, err := checker.GenerateSyncSummaryJSON(summary)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	} else {
+		fmt.Print("\n" + checker.GenerateSyncSummary(summary, newReportOptions(false, verbose)))
+	}
+
+	if len(summary.StillMissing) > 0 {
+		return fmt.Errorf("sync left %d variables missing: %s", len(summary.StillMissing), strings.Join(summary.StillMissing, ", "))
+	}
+
+	return nil
+}
+
+// countPresent counts example keys already set in env
+func countPresent(env, example parser.EnvVars) int {
+	present := 0
+	for key := range example {
+		if env.Has(key) {
+			present++
+		}
+	}
+	return present
+}
+
+// syncSeparator marks the block of variables appended by sync
+const syncSeparator = "# Added by envquack sync"
+
+// appendVars appends assignments for keys to the env file under a separator
+// comment, taking values from vars (nil for empty values)
+func appendVars(filename, separator string, keys []string, vars parser.EnvVars) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return appendBlock(filename, separator, parser.FormatEnvBlock(keys, vars))
+}
+
+// appendBlock appends lines to the env file, under a separator comment when
+// the file already has content
+func appendBlock(filename, separator, lines string) error {
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open env file for writing: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(appendedText(existing, separator, lines)); err != nil {
+		return fmt.Errorf("failed to write variables: %w", err)
+	}
+
+	return nil
+}
+
+// appendedText returns what appendBlock adds after existing content
+func appendedText(existing []byte, separator, lines string) string {
+	var block strings.Builder
+
+	// Never glue the first new key onto an unterminated last line
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		block.WriteString("\n")
+	}
+
+	// Add a separator comment if file already has content
+	if len(strings.TrimSpace(string(existing))) > 0 {
+		block.WriteString("\n" + separator + "\n")
+	}
+
+	block.WriteString(lines)
+	return block.String()
+}
+
+// printSyncPatch prints the change sync would make to the env file as a
+// unified diff, leaving the file untouched
+func printSyncPatch(filename string, keys []string, values parser.EnvVars, prune []string) error {
+	if len(keys) == 0 && len(prune) == 0 {
+		return nil
+	}
+
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	proposed, err := parser.RemoveEntries(string(existing), prune)
+	if err != nil {
+		return fmt.Errorf("failed to parse env file: %w", err)
+	}
+	if len(keys) > 0 {
+		proposed += appendedText([]byte(proposed), syncSeparator, parser.FormatEnvBlock(keys, values))
+	}
+	fmt.Print(parser.UnifiedDiff(filepath.ToSlash(filename), string(existing), proposed))
+	return nil
+}
+
+// printSyncDryRun prints the exact lines sync would append to the env file,
+// separator included, leaving the file untouched
+func printSyncDryRun(out io.Writer, filename string, keys []string, values parser.EnvVars) error {
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	fmt.Fprintf(out, "Would add %d missing variables to %s (dry run, nothing written):\n", len(keys), filename)
+	block := strings.TrimLeft(appendedText(existing, syncSeparator, parser.FormatEnvBlock(keys, values)), "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(block, "\n"), "\n") {
+		fmt.Fprintf(out, "  + %s\n", line)
+	}
+	return nil
+}
+
+// printSyncPruneDryRun lists the keys --prune would remove, leaving the file
+// untouched
+func printSyncPruneDryRun(out io.Writer, filename string, keys []string) {
+	if len(keys) == 0 {
+		fmt.Fprintf(out, "No extra variables to remove from %s.\n", filename)
+		return
+	}
+	fmt.Fprintf(out, "Would remove %d extra variables from %s (dry run, nothing written):\n", len(keys), filename)
+	for _, key := range keys {
+		fmt.Fprintf(out, "  - %s\n", key)
+	}
+}
+
+// pruneEnvFile removes the assignments of keys from the env file, with the
+// comments directly above them, after backing the file up to <file>.bak.
+// Unless --force is given it asks first, and reports whether it pruned.
+func pruneEnvFile(out io.Writer, filename string, keys []string) (bool, error) {
+	fmt.Fprintf(out, "Removing %d extra variables from %s (not in %s):\n", len(keys), filename, exampleFile)
+	for _, key := range keys {
+		fmt.Fprintf(out, "  - %s\n", key)
+	}
+
+	if !syncForce {
+		ok, err := confirm(fmt.Sprintf("Remove them from %s?", filename))
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			fmt.Fprintf(out, "Left %s as is.\n\n", filename)
+			return false, nil
+		}
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false, fmt.Errorf("failed to read env file: %w", err)
+	}
+	existing, err := os.ReadFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	content, err := parser.RemoveEntries(string(existing), keys)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse env file: %w", err)
+	}
+
+	backup := filename + ".bak"
+	if err := os.WriteFile(backup, existing, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to back up env file: %w", err)
+	}
+	if err := os.WriteFile(filename, []byte(content), info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	fmt.Fprintf(out, "%sRemoved %d variables, the original is saved as %s\n\n", icon("🧹"), len(keys), backup)
+	return true, nil
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin;
+// anything but y or yes is a no. Without a terminal there is no one to ask.
+func confirm(question string) (bool, error) {
+	if !isTerminal(os.Stdin) {
+		return false, fmt.Errorf("can't ask for confirmation without a terminal on stdin; use --force")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// missingAfterSync re-parses the env file and returns example keys still
+// missing. It bypasses the parse cache: a rewrite within the same clock tick
+// that keeps the size would look unchanged to it.
+func missingAfterSync(filename string, example *parser.ParsedFile, opts *parser.ParseOptions) ([]string, error) {
+	parsed, err := parser.ParseEnvFileWithOptions(filename, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-read env file: %w", err)
+	}
+
+	env := parsed.EnvVars()
+	result := checker.CompareEnvVars(env, example.EnvVars())
+	checker.ApplyDeprecations(result, env, example)
+	return result.Missing, nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSyncIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		example string
+		args    []string
+		added   bool
+	}{
+		{"appends missing keys", "A=1\n", "A=\nB=\nC=\n", nil, true},
+		{"no trailing newline", "A=1", "A=\nB=\n", nil, true},
+		{"with values", "A=1\n", "A=\nB=default\n", []string{"--with-values"}, true},
+		{"quoted and exported", "export A=\"x y\"\n", "A=\nB='z'\n", []string{"--with-values"}, true},
+		{"nothing missing", "A=1\nB=2\n", "A=\nB=\n", nil, false},
+		{"nothing missing in INI", "[db]\nhost=x\n", "DB_HOST=\n", []string{"--ini"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			env := writeFile(t, dir, ".env", tt.env)
+			example := writeFile(t, dir, ".env.example", tt.example)
+			args := append([]string{"sync", "--env", env, "--example", example}, tt.args...)
+
+			if err := run(t, args...); err != nil {
+				t.Fatalf("first sync: %v", err)
+			}
+			first, err := os.ReadFile(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 0
+			if tt.added {
+				want = 1
+			}
+			if n := strings.Count(string(first), syncSeparator); n != want {
+				t.Fatalf("first sync wrote %d separators, want %d:\n%s", n, want, first)
+			}
+
+			if err := run(t, args...); err != nil {
+				t.Fatalf("second sync: %v", err)
+			}
+			second, err := os.ReadFile(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(second) != string(first) {
+				t.Errorf("second sync changed .env:\n--- first\n%s\n--- second\n%s", first, second)
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, ".env.bak")); err == nil {
+				t.Error("sync without --prune wrote a backup")
+			}
+		})
+	}
+}
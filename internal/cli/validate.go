@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/DuckDHD/EnvQuack/internal/checker"
+	"github.com/spf13/cobra"
+)
+
+// defaultSchemaFile is the schema validate reads when --schema is not given
+const defaultSchemaFile = ".env.schema"
+
+var schemaFile string
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check .env against the types declared in .env.schema",
+	Long: `Validate checks .env against a schema of KEY:type:required lines:
+
+  PORT:int:required
+  DEBUG:bool:optional
+  API_URL:url
+
+Types are string, int, bool and url; the last part defaults to required.
+Required variables must be set to a non-empty value, and every value that is
+set must parse as its type.
+
+Exits non-zero when any variable violates the schema.`,
+	Args: cobra.NoArgs,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&schemaFile, "schema", defaultSchemaFile, "path to the schema file")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unsupported format %q for validate (use text or json)", outputFormat)
+	}
+
+	if err := checkFileExists(schemaFile); err != nil {
+		return fmt.Errorf("schema file error: %w", err)
+	}
+	if err := checkFileExists(envFile); err != nil {
+		return fmt.Errorf("env file error: %w", err)
+	}
+
+	parseOpts, err := newParseOptions()
+	if err != nil {
+		return err
+	}
+
+	result, err := checker.ValidateEnvFileSchema(envFile, schemaFile, parseOpts)
+	if err != nil {
+		return fmt.Errorf("failed to validate env file: %w", err)
+	}
+
+	if outputFormat == "json" {
+		report, err := checker.GenerateSchemaJSON(result)
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+	} else {
+		fmt.Print(checker.GenerateSchemaReport(result, newReportOptions(!noDuck, verbose)))
+	}
+
+	if result.HasIssues() {
+		return exitStatus(1)
+	}
+
+	return nil
+}
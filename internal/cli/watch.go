@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileStamp is what a poll compares to notice a change; a missing file has
+// the zero stamp, so creating or deleting it counts as a change
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// runWatch checks once, then again whenever a watched file changes, until
+// interrupted. Findings never stop the loop, and errors such as a file that
+// is half written are printed and retried on the next change.
+func runWatch(args []string) error {
+	if interactive {
+		return fmt.Errorf("--watch can't be combined with --interactive")
+	}
+	if watchInterval <= 0 {
+		return fmt.Errorf("--watch-interval must be positive, got %s", watchInterval)
+	}
+
+	files, err := watchedFiles(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return watchFiles(ctx, files, watchInterval, func(changed []string) {
+		if changed != nil {
+			startWatchRun(changed)
+		}
+		// Every run must see the files as they are now
+		useParseCache()
+		var exit *ExitError
+		if err := checkOnce(args); err != nil && !errors.As(err, &exit) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Printf("\n%sWatching %d files for changes (Ctrl-C to stop)...\n", icon("👀"), len(files))
+	})
+}
+
+// watchedFiles returns the files a check reads: the env files, the example
+// and the compose file
+func watchedFiles(args []string) ([]string, error) {
+	files := []string{exampleFile, composeFile}
+	if len(args) > 0 || multipleEnvFiles() {
+		patterns := args
+		if len(args) == 0 {
+			patterns = envPatterns
+		}
+		matched, err := expandEnvFiles(patterns)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matched...)
+	} else {
+		files = append(files, envFile)
+	}
+	return files, nil
+}
+
+// watchFiles calls run once, then polls files every interval and calls run
+// with the files that changed, until ctx is done
+func watchFiles(ctx context.Context, files []string, interval time.Duration, run func(changed []string)) error {
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		stamps[file] = statFile(file)
+	}
+	run(nil)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+
+		var changed []string
+		for _, file := range files {
+			if stamp := statFile(file); stamp != stamps[file] {
+				stamps[file] = stamp
+				changed = append(changed, file)
+			}
+		}
+		if len(changed) > 0 {
+			run(changed)
+		}
+	}
+}
+
+// statFile returns the stamp of file, zero if it can't be read
+func statFile(file string) fileStamp {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// startWatchRun clears the terminal, or separates runs when the output is
+// not a terminal or colors are disabled, and names the changed files
+func startWatchRun(changed []string) {
+	if isTerminal(os.Stdout) && !noColor {
+		fmt.Print("\033[H\033[2J")
+	} else {
+		fmt.Println("\n----------------------------------------")
+	}
+
+	names := make([]string, 0, len(changed))
+	for _, file := range changed {
+		names = append(names, filepath.Base(file))
+	}
+	fmt.Printf("%s %s changed, re-checking\n\n", time.Now().Format("15:04:05"), strings.Join(names, ", "))
+}
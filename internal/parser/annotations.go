@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Annotations maps annotation names (without the @) to their argument text,
+// e.g. `# @deprecated use API_URL instead` gives {"deprecated": "use API_URL instead"}
+type Annotations map[string]string
+
+// annotationRegex matches an @name token at the start of a comment or after whitespace
+var annotationRegex = regexp.MustCompile(`(?:^|\s)@([a-z][a-z0-9_-]*)`)
+
+// Has checks if an annotation is present
+func (a Annotations) Has(name string) bool {
+	_, exists := a[name]
+	return exists
+}
+
+// isAnnotationComment reports whether comment text holds annotations
+func isAnnotationComment(text string) bool {
+	return strings.HasPrefix(text, "@")
+}
+
+// parseAnnotationComment adds the annotations in comment text to annotations.
+// Several annotations may share a line: `# @required @type int`.
+func parseAnnotationComment(text string, annotations Annotations) {
+	matches := annotationRegex.FindAllStringSubmatchIndex(text, -1)
+	for i, match := range matches {
+		name := text[match[2]:match[3]]
+
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		annotations[name] = strings.TrimSpace(text[match[1]:end])
+	}
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache memoizes parse results within a single invocation. Entries are keyed
+// by file path and invalidated whenever the file's modification time or size
+// changes, so a long-running watcher always sees fresh content.
+//
+// A nil *Cache is valid and parses on every call. Cached compose and
+// Dockerfile results are shared and must be treated as read-only.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+// cacheKey identifies a parse result by parser kind, absolute path and, for
+// env files, the options the file was parsed with
+type cacheKey struct {
+	kind string
+	path string
+	opts ParseOptions
+}
+
+// cacheEntry is a parse result along with the file state it was parsed from
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	value   interface{}
+}
+
+// NewCache creates an empty parse cache
+func NewCache() *Cache {
+	return &Cache{entries: make(map[cacheKey]cacheEntry)}
+}
+
+// ParseEnvFile parses an env file, reusing the previous result if unchanged
+func (c *Cache) ParseEnvFile(filename string) (EnvVars, error) {
+	parsed, err := c.ParseEnvFileWithOptions(filename, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.EnvVars(), nil
+}
+
+// ParseEnvFileWithOptions parses an env file like ParseEnvFileWithOptions,
+// reusing the previous result if the file is unchanged. Results parsed with
+// different options are cached separately.
+func (c *Cache) ParseEnvFileWithOptions(filename string, opts *ParseOptions) (*ParsedFile, error) {
+	if opts == nil {
+		opts = DefaultParseOptions()
+	}
+
+	value, err := c.load(cacheKey{kind: "env", opts: *opts}, filename, func() (interface{}, error) {
+		return ParseEnvFileWithOptions(filename, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers commonly append to or rewrite entries, so hand out a copy
+	cached := value.(*ParsedFile)
+	return &ParsedFile{
+		Filename:  cached.Filename,
+		Entries:   append([]EnvEntry(nil), cached.Entries...),
+		Commented: append([]EnvEntry(nil), cached.Commented...),
+		Comments:  append([]Comment(nil), cached.Comments...),
+	}, nil
+}
+
+// ParseComposeFile parses a compose file, reusing the previous result if unchanged
+func (c *Cache) ParseComposeFile(filename string) (*ComposeEnvInfo, error) {
+	value, err := c.load(cacheKey{kind: "compose"}, filename, func() (interface{}, error) {
+		return ParseComposeFile(filename)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*ComposeEnvInfo), nil
+}
+
+// ParseDockerfile parses a Dockerfile, reusing the previous result if unchanged
+func (c *Cache) ParseDockerfile(filename string) (*DockerfileEnvInfo, error) {
+	value, err := c.load(cacheKey{kind: "dockerfile"}, filename, func() (interface{}, error) {
+		return ParseDockerfile(filename)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*DockerfileEnvInfo), nil
+}
+
+// load returns the cached result for filename under key, whose path it fills
+// in, or runs parse and caches it. Errors are never cached.
+func (c *Cache) load(key cacheKey, filename string, parse func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return parse()
+	}
+
+	stat, err := os.Stat(filename)
+	if err != nil {
+		// Let the parser report the error in its own words
+		return parse()
+	}
+
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		path = filename
+	}
+	key.path = path
+
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	c.mu.Unlock()
+
+	if exists && entry.modTime.Equal(stat.ModTime()) && entry.size == stat.Size() {
+		return entry.value, nil
+	}
+
+	value, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{modTime: stat.ModTime(), size: stat.Size(), value: value}
+	c.mu.Unlock()
+
+	return value, nil
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheParseEnvFileWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	if err := os.WriteFile(path, []byte("[db]\nhost=localhost\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewCache()
+
+	tests := []struct {
+		name    string
+		opts    *ParseOptions
+		wantKey string
+	}{
+		{"plain", nil, "host"},
+		{"ini", &ParseOptions{INI: true}, "DB_HOST"},
+		{"plain again", DefaultParseOptions(), "host"},
+		{"ini again", &ParseOptions{INI: true}, "DB_HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := cache.ParseEnvFileWithOptions(path, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := parsed.EnvVars()[tt.wantKey]; !ok {
+				t.Errorf("keys = %v, want %s", parsed.Keys(), tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidatesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("A=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewCache()
+	if vars, err := cache.ParseEnvFile(path); err != nil || vars["A"] != "1" {
+		t.Fatalf("first parse = %v, %v", vars, err)
+	}
+
+	if err := os.WriteFile(path, []byte("A=22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if vars, err := cache.ParseEnvFile(path); err != nil || vars["A"] != "22" {
+		t.Errorf("parse after change = %v, %v, want A=22", vars, err)
+	}
+}
+
+func TestCacheHandsOutCopies(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("A=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewCache()
+	first, err := cache.ParseEnvFileWithOptions(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Entries[0].Value = "changed"
+
+	second, err := cache.ParseEnvFileWithOptions(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Entries[0].Value != "1" {
+		t.Errorf("cached entry was modified through a previous result: %q", second.Entries[0].Value)
+	}
+}
+
+func TestNilCacheParses(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("A=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cache *Cache
+	if vars, err := cache.ParseEnvFile(path); err != nil || vars["A"] != "1" {
+		t.Errorf("nil cache parse = %v, %v", vars, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -15,6 +17,8 @@ import (
 type ComposeService struct {
 	Environment interface{} `yaml:"environment"`
 	EnvFile     interface{} `yaml:"env_file"`
+	Extends     interface{} `yaml:"extends"`
+	Ports       interface{} `yaml:"ports"`
 }
 
 // ComposeFile represents the structure of a docker-compose.yml
@@ -29,6 +33,13 @@ type ComposeEnvInfo struct {
 	ServiceVars  map[string]EnvVars // Variables by service name
 	EnvFiles     []string           // Referenced env_file paths
 	VariableRefs []string           // Variables referenced as ${VAR} or $VAR
+
+	ServiceNames    []string            // All services, including those without environment
+	ServiceEnvFiles map[string][]string // env_file paths by service name
+
+	Locations   map[string][]ComposeLocation // Where each variable is defined or referenced
+	ServiceRefs map[string][]string          // Variables referenced anywhere in each service (healthcheck, deploy, ...)
+	PortRefs    map[string][]string          // Variables referenced in each service's ports mappings
 }
 
 // ParseComposeFile parses a docker-compose.yml file and extracts environment variables
@@ -44,54 +55,229 @@ func ParseComposeFile(filename string) (*ComposeEnvInfo, error) {
 		return nil, fmt.Errorf("failed to read compose file: %w", err)
 	}
 
-	return ParseComposeData(data)
+	return parseComposeData(data, filename)
 }
 
-// ParseComposeData parses docker-compose YAML data
+// ParseComposeData parses docker-compose YAML data. Cross-file extends are
+// resolved relative to the working directory.
 func ParseComposeData(data []byte) (*ComposeEnvInfo, error) {
+	return parseComposeData(data, "")
+}
+
+// parseComposeData parses compose YAML read from filename ("" for in-memory data)
+func parseComposeData(data []byte, filename string) (*ComposeEnvInfo, error) {
 	var compose ComposeFile
 	if err := yaml.Unmarshal(data, &compose); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
+	// The full service definitions, for references outside environment
+	var raw struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	resolver := &extendsResolver{files: make(map[string]*ComposeFile)}
+
 	info := &ComposeEnvInfo{
-		Variables:    make(EnvVars),
-		ServiceVars:  make(map[string]EnvVars),
-		EnvFiles:     []string{},
-		VariableRefs: []string{},
+		Variables:       make(EnvVars),
+		ServiceVars:     make(map[string]EnvVars),
+		EnvFiles:        []string{},
+		VariableRefs:    []string{},
+		ServiceNames:    []string{},
+		ServiceEnvFiles: make(map[string][]string),
+		Locations:       make(map[string][]ComposeLocation),
+		ServiceRefs:     make(map[string][]string),
+		PortRefs:        make(map[string][]string),
 	}
 
 	// Extract variables from each service
-	for serviceName, service := range compose.Services {
-		serviceVars := make(EnvVars)
+	for serviceName := range compose.Services {
+		// Resolve the effective environment including extends
+		serviceVars, envFiles, err := resolver.resolveService(&compose, filename, serviceName, nil)
+		if err != nil {
+			return nil, err
+		}
 
-		// Parse environment variables
-		envVars := parseEnvironmentSection(service.Environment)
-		for k, v := range envVars {
+		for k, v := range serviceVars {
 			info.Variables[k] = v
-			serviceVars[k] = v
 		}
 
-		// Parse env_file references
-		envFiles := parseEnvFileSection(service.EnvFile)
+		// Collect env_file references
 		info.EnvFiles = append(info.EnvFiles, envFiles...)
+		info.ServiceNames = append(info.ServiceNames, serviceName)
+		if len(envFiles) > 0 {
+			info.ServiceEnvFiles[serviceName] = removeDuplicates(envFiles)
+		}
 
 		// Store service-specific variables
 		if len(serviceVars) > 0 {
 			info.ServiceVars[serviceName] = serviceVars
 		}
+
+		// References anywhere in the service, plus those in inherited values
+		refSet := make(map[string]bool)
+		collectServiceRefs(raw.Services[serviceName], refSet)
+		for _, v := range serviceVars {
+			collectComposeRefs(v, refSet)
+		}
+		if len(refSet) > 0 {
+			refs := make([]string, 0, len(refSet))
+			for ref := range refSet {
+				refs = append(refs, ref)
+			}
+			sort.Strings(refs)
+			info.ServiceRefs[serviceName] = refs
+		}
+
+		// References in port mappings, like "${HOST_PORT}:8080"
+		portSet := make(map[string]bool)
+		collectServiceRefs(compose.Services[serviceName].Ports, portSet)
+		if len(portSet) > 0 {
+			refs := make([]string, 0, len(portSet))
+			for ref := range portSet {
+				refs = append(refs, ref)
+			}
+			sort.Strings(refs)
+			info.PortRefs[serviceName] = refs
+		}
 	}
 
 	// Extract variable references from the entire YAML content
-	info.VariableRefs = extractVariableReferences(string(data))
+	var refLines map[string][]int
+	info.VariableRefs, refLines = extractVariableReferences(string(data))
+	info.Locations = locateComposeVars(data, filename, refLines)
 
 	// Remove duplicates from env files
 	info.EnvFiles = removeDuplicates(info.EnvFiles)
 	sort.Strings(info.EnvFiles)
+	sort.Strings(info.ServiceNames)
 
 	return info, nil
 }
 
+// extendsResolver resolves service extends across compose files
+type extendsResolver struct {
+	files map[string]*ComposeFile // Loaded external compose files by path
+}
+
+// extendsTarget is the service an extends entry points at
+type extendsTarget struct {
+	Service string
+	File    string
+}
+
+// resolveService returns the effective environment and env_files of a
+// service, merging in anything inherited via extends (child wins)
+func (r *extendsResolver) resolveService(compose *ComposeFile, filename, serviceName string, chain []string) (EnvVars, []string, error) {
+	id := composeDisplayName(filename) + ":" + serviceName
+	for _, seen := range chain {
+		if seen == id {
+			return nil, nil, fmt.Errorf("circular extends: %s -> %s", strings.Join(chain, " -> "), id)
+		}
+	}
+	chain = append(chain, id)
+
+	service, exists := compose.Services[serviceName]
+	if !exists {
+		if len(chain) > 1 {
+			return nil, nil, fmt.Errorf("extends target %s not found (extended by %s)", id, chain[len(chain)-2])
+		}
+		return nil, nil, fmt.Errorf("service %s not found", id)
+	}
+
+	vars := make(EnvVars)
+	var envFiles []string
+
+	// Start from the base service's environment
+	if service.Extends != nil {
+		target, err := parseExtendsSection(service.Extends)
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", id, err)
+		}
+
+		baseCompose, baseFilename := compose, filename
+		if target.File != "" {
+			baseFilename = filepath.Join(filepath.Dir(filename), target.File)
+			baseCompose, err = r.load(baseFilename)
+			if err != nil {
+				return nil, nil, fmt.Errorf("service %s extends %s: %w", id, target.File, err)
+			}
+		}
+
+		baseVars, baseFiles, err := r.resolveService(baseCompose, baseFilename, target.Service, chain)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for k, v := range baseVars {
+			vars[k] = v
+		}
+		envFiles = append(envFiles, baseFiles...)
+	}
+
+	// Local definitions override inherited ones
+	for k, v := range parseEnvironmentSection(service.Environment) {
+		vars[k] = v
+	}
+	// env_file paths are relative to the compose file, like docker compose reads them
+	for _, envFile := range parseEnvFileSection(service.EnvFile) {
+		if filename != "" && !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(filepath.Dir(filename), envFile)
+		}
+		envFiles = append(envFiles, envFile)
+	}
+
+	return vars, envFiles, nil
+}
+
+// load reads an external compose file, caching it for repeated extends
+func (r *extendsResolver) load(filename string) (*ComposeFile, error) {
+	if compose, exists := r.files[filename]; exists {
+		return compose, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var compose ComposeFile
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML in %s: %w", filename, err)
+	}
+
+	r.files[filename] = &compose
+	return &compose, nil
+}
+
+// parseExtendsSection handles both `extends: base` and `extends: {service, file}`
+func parseExtendsSection(extends interface{}) (extendsTarget, error) {
+	switch e := extends.(type) {
+	case string:
+		return extendsTarget{Service: e}, nil
+	case map[string]interface{}:
+		target := extendsTarget{}
+		target.Service, _ = e["service"].(string)
+		target.File, _ = e["file"].(string)
+		if target.Service == "" {
+			return target, fmt.Errorf("extends is missing a service name")
+		}
+		return target, nil
+	}
+	return extendsTarget{}, fmt.Errorf("invalid extends format")
+}
+
+// composeDisplayName names a compose source in error messages
+func composeDisplayName(filename string) string {
+	if filename == "" {
+		return "compose"
+	}
+	return filename
+}
+
 // parseEnvironmentSection handles different formats of environment sections
 func parseEnvironmentSection(env interface{}) EnvVars {
 	vars := make(EnvVars)
@@ -164,38 +350,116 @@ func parseEnvString(str string) (string, string) {
 	return str, ""
 }
 
-// extractVariableReferences finds ${VAR} and $VAR references in the compose file
-func extractVariableReferences(content string) []string {
-	// Regex patterns for variable references
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`\$\{([A-Z_][A-Z0-9_]*)\}`),        // ${VAR_NAME}
-		regexp.MustCompile(`\$\{([A-Z_][A-Z0-9_]*):?[^}]*\}`), // ${VAR_NAME:-default}
-		regexp.MustCompile(`\$([A-Z_][A-Z0-9_]*)`),            // $VAR_NAME
-	}
+// composeNameRegex matches the variable name at the start of a reference
+var composeNameRegex = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*`)
 
-	varSet := make(map[string]bool)
+// extractVariableReferences finds ${VAR} and $VAR references in the compose
+// file. It scans line by line so every reference keeps its line numbers.
+func extractVariableReferences(content string) ([]string, map[string][]int) {
+	refLines := make(map[string][]int)
 
-	for _, pattern := range patterns {
-		matches := pattern.FindAllStringSubmatch(content, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				varName := match[1]
-				// Filter out common docker variables that aren't typically in .env
-				if !isDockerInternalVar(varName) {
-					varSet[varName] = true
-				}
-			}
+	for i, line := range strings.Split(content, "\n") {
+		lineSet := make(map[string]bool)
+		collectComposeRefs(line, lineSet)
+		for varName := range lineSet {
+			refLines[varName] = append(refLines[varName], i+1)
 		}
 	}
 
 	// Convert to sorted slice
-	vars := make([]string, 0, len(varSet))
-	for varName := range varSet {
+	vars := make([]string, 0, len(refLines))
+	for varName := range refLines {
 		vars = append(vars, varName)
 	}
 	sort.Strings(vars)
 
-	return vars
+	return vars, refLines
+}
+
+// collectComposeRefs adds the references found in content to varSet. `$$`
+// escapes are skipped, so `$$VAR` is never reported. A braced expression runs
+// to its balancing brace, which reads the name from any bash parameter
+// expansion (`${#VAR}`, `${VAR:0:8}`, `${VAR//a/b}`, `${PATH##*/}`) and
+// reports nothing from the rest except real references, as in `${A:-${B}}`
+// or `${A/x/$B}`.
+func collectComposeRefs(content string, varSet map[string]bool) {
+	for i := 0; i+1 < len(content); i++ {
+		if content[i] != '$' {
+			continue
+		}
+
+		switch content[i+1] {
+		case '$':
+			i++
+		case '{':
+			end := closingBrace(content, i+2)
+			if end < 0 {
+				return
+			}
+			name, modifier := splitBracedExpansion(content[i+2 : end])
+			addComposeRef(name, varSet)
+			collectComposeRefs(modifier, varSet)
+			i = end
+		default:
+			name := composeNameRegex.FindString(content[i+1:])
+			addComposeRef(name, varSet)
+			i += len(name)
+		}
+	}
+}
+
+// closingBrace returns the index of the brace closing an expression whose
+// content starts at start, or -1 if it is unterminated
+func closingBrace(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitBracedExpansion splits the inside of `${...}` into the variable name
+// and the modifier after it. A leading # (length) or ! (indirection) is not
+// part of the name.
+func splitBracedExpansion(inner string) (string, string) {
+	if len(inner) > 1 && (inner[0] == '#' || inner[0] == '!') {
+		inner = inner[1:]
+	}
+	name := composeNameRegex.FindString(inner)
+	return name, inner[len(name):]
+}
+
+// addComposeRef adds a referenced variable to varSet, ignoring Docker's own
+func addComposeRef(name string, varSet map[string]bool) {
+	// Filter out common docker variables that aren't typically in .env
+	if name != "" && !isDockerInternalVar(name) {
+		varSet[name] = true
+	}
+}
+
+// collectServiceRefs walks every string value in a service definition
+// (healthcheck.test, deploy.labels, image, ...) and adds its references to varSet
+func collectServiceRefs(node interface{}, varSet map[string]bool) {
+	switch n := node.(type) {
+	case string:
+		collectComposeRefs(n, varSet)
+	case map[string]interface{}:
+		for _, value := range n {
+			collectServiceRefs(value, varSet)
+		}
+	case []interface{}:
+		for _, item := range n {
+			collectServiceRefs(item, varSet)
+		}
+	}
 }
 
 // isDockerInternalVar checks if a variable is a Docker/Compose internal variable
@@ -254,6 +518,163 @@ func (c *ComposeEnvInfo) GetAllEnvVars() []string {
 	return vars
 }
 
+// ServicesWithoutEnv returns the services that declare neither environment
+// nor env_file (directly or through extends), sorted by name
+func (c *ComposeEnvInfo) ServicesWithoutEnv() []string {
+	services := []string{}
+	for _, name := range c.ServiceNames {
+		if len(c.ServiceVars[name]) == 0 && len(c.ServiceEnvFiles[name]) == 0 {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// EnvFileServices maps each env_file to the services referencing it
+// (directly or through extends), sorted by name
+func (c *ComposeEnvInfo) EnvFileServices() map[string][]string {
+	services := make(map[string][]string)
+	for _, name := range c.ServiceNames {
+		for _, envFile := range c.ServiceEnvFiles[name] {
+			services[envFile] = append(services[envFile], name)
+		}
+	}
+	return services
+}
+
+// MatchServices resolves service name patterns to service names. Patterns are
+// globs (worker-*) and are OR-ed together; a pattern starting with ! removes
+// matching services afterwards, so "worker-*,!worker-2" is every worker but
+// worker-2. With only exclusions, selection starts from all services. Patterns
+// that match no service are returned as unmatched.
+func (c *ComposeEnvInfo) MatchServices(patterns []string) ([]string, []string, error) {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	unmatched := []string{}
+	hasInclude := false
+
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		glob := strings.TrimPrefix(pattern, "!")
+		if !negated {
+			hasInclude = true
+		}
+
+		matched := false
+		for _, name := range c.ServiceNames {
+			ok, err := path.Match(glob, name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid service pattern %q: %w", pattern, err)
+			}
+			if !ok {
+				continue
+			}
+
+			matched = true
+			if negated {
+				exclude[name] = true
+			} else {
+				include[name] = true
+			}
+		}
+
+		if !matched {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+
+	selected := []string{}
+	for _, name := range c.ServiceNames {
+		if (include[name] || !hasInclude) && !exclude[name] {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, unmatched, nil
+}
+
+// FilterServices returns a copy of the compose info restricted to the named
+// services, along with any names that are not services in the file. Variable
+// references are narrowed to those made by the selected services.
+func (c *ComposeEnvInfo) FilterServices(names []string) (*ComposeEnvInfo, []string) {
+	known := make(map[string]bool, len(c.ServiceNames))
+	for _, name := range c.ServiceNames {
+		known[name] = true
+	}
+
+	filtered := &ComposeEnvInfo{
+		Variables:       make(EnvVars),
+		ServiceVars:     make(map[string]EnvVars),
+		EnvFiles:        []string{},
+		VariableRefs:    []string{},
+		ServiceNames:    []string{},
+		ServiceEnvFiles: make(map[string][]string),
+		Locations:       make(map[string][]ComposeLocation),
+		ServiceRefs:     make(map[string][]string),
+		PortRefs:        make(map[string][]string),
+	}
+
+	unknown := []string{}
+	refSet := make(map[string]bool)
+	for _, name := range removeDuplicates(names) {
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		filtered.ServiceNames = append(filtered.ServiceNames, name)
+
+		if vars, exists := c.ServiceVars[name]; exists {
+			filtered.ServiceVars[name] = vars
+			for k, v := range vars {
+				filtered.Variables[k] = v
+			}
+		}
+
+		if refs, exists := c.ServiceRefs[name]; exists {
+			filtered.ServiceRefs[name] = refs
+			for _, ref := range refs {
+				refSet[ref] = true
+			}
+		}
+
+		if refs, exists := c.PortRefs[name]; exists {
+			filtered.PortRefs[name] = refs
+		}
+
+		if envFiles, exists := c.ServiceEnvFiles[name]; exists {
+			filtered.ServiceEnvFiles[name] = envFiles
+			filtered.EnvFiles = append(filtered.EnvFiles, envFiles...)
+		}
+	}
+
+	for ref := range refSet {
+		filtered.VariableRefs = append(filtered.VariableRefs, ref)
+	}
+	sort.Strings(filtered.VariableRefs)
+
+	// Keep only locations inside the selected services
+	selected := make(map[string]bool, len(filtered.ServiceNames))
+	for _, name := range filtered.ServiceNames {
+		selected[name] = true
+	}
+	for key, locs := range c.Locations {
+		for _, loc := range locs {
+			if selected[loc.Service] {
+				filtered.Locations[key] = append(filtered.Locations[key], loc)
+			}
+		}
+	}
+
+	filtered.EnvFiles = removeDuplicates(filtered.EnvFiles)
+	sort.Strings(filtered.EnvFiles)
+	sort.Strings(filtered.ServiceNames)
+	sort.Strings(unknown)
+
+	return filtered, unknown
+}
+
 // GetServiceVars returns variables for a specific service
 func (c *ComposeEnvInfo) GetServiceVars(serviceName string) EnvVars {
 	if vars, exists := c.ServiceVars[serviceName]; exists {
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeLocation is where a variable is defined or referenced in a compose file
+type ComposeLocation struct {
+	File    string // Compose file path, "" for in-memory data
+	Line    int    // 1-based line number
+	Service string // Enclosing service, "" outside services
+}
+
+// String formats the location as file:line, followed by the service in brackets if any
+func (l ComposeLocation) String() string {
+	pos := fmt.Sprintf("line %d", l.Line)
+	if l.File != "" {
+		pos = fmt.Sprintf("%s:%d", l.File, l.Line)
+	}
+	if l.Service != "" {
+		pos += " [" + l.Service + "]"
+	}
+	return pos
+}
+
+// serviceSpan is the line range [start, end) of a service definition
+type serviceSpan struct {
+	name  string
+	start int
+	end   int
+}
+
+// locateComposeVars combines the reference lines found by
+// extractVariableReferences with the lines of keys defined in environment
+// sections, attributing each to its enclosing service. Variables inherited
+// via extends from another file are only located at their uses in this file.
+func locateComposeVars(data []byte, filename string, refLines map[string][]int) map[string][]ComposeLocation {
+	locations := make(map[string][]ComposeLocation)
+
+	var root yaml.Node
+	spans := []serviceSpan{}
+	if err := yaml.Unmarshal(data, &root); err == nil {
+		spans = composeServiceSpans(&root)
+		for _, span := range spans {
+			for key, line := range composeEnvironmentLines(&root, span.name) {
+				locations[key] = append(locations[key], ComposeLocation{File: filename, Line: line, Service: span.name})
+			}
+		}
+	}
+
+	for ref, lines := range refLines {
+		for _, line := range lines {
+			locations[ref] = append(locations[ref], ComposeLocation{File: filename, Line: line, Service: serviceAtLine(spans, line)})
+		}
+	}
+
+	for _, locs := range locations {
+		sort.Slice(locs, func(i, j int) bool { return locs[i].Line < locs[j].Line })
+	}
+
+	return locations
+}
+
+// composeServiceSpans returns the line range of each service under services:
+func composeServiceSpans(root *yaml.Node) []serviceSpan {
+	if len(root.Content) == 0 {
+		return nil
+	}
+	top := root.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var spans []serviceSpan
+	for i := 0; i+1 < len(top.Content); i += 2 {
+		if top.Content[i].Value != "services" || top.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+
+		// The services block ends where the next top-level key starts
+		blockEnd := int(^uint(0) >> 1)
+		if i+2 < len(top.Content) {
+			blockEnd = top.Content[i+2].Line
+		}
+
+		services := top.Content[i+1].Content
+		for j := 0; j+1 < len(services); j += 2 {
+			span := serviceSpan{name: services[j].Value, start: services[j].Line, end: blockEnd}
+			if j+2 < len(services) {
+				span.end = services[j+2].Line
+			}
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+// composeEnvironmentLines maps the keys defined in a service's environment
+// section to their line numbers
+func composeEnvironmentLines(root *yaml.Node, service string) map[string]int {
+	lines := make(map[string]int)
+
+	env := mappingValue(mappingValue(mappingValue(root.Content[0], "services"), service), "environment")
+	if env == nil {
+		return lines
+	}
+
+	switch env.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(env.Content); i += 2 {
+			lines[env.Content[i].Value] = env.Content[i].Line
+		}
+	case yaml.SequenceNode:
+		for _, item := range env.Content {
+			if key, _ := parseEnvString(item.Value); key != "" {
+				lines[key] = item.Line
+			}
+		}
+	}
+
+	return lines
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// serviceAtLine returns the service whose definition contains line, or ""
+func serviceAtLine(spans []serviceSpan, line int) string {
+	for _, span := range spans {
+		if line >= span.start && line < span.end {
+			return span.name
+		}
+	}
+	return ""
+}
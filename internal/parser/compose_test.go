@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestExtractVariableReferences(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantVars  []string
+		wantLines map[string][]int
+	}{
+		{
+			name:      "braced",
+			content:   "image: ${IMAGE}",
+			wantVars:  []string{"IMAGE"},
+			wantLines: map[string][]int{"IMAGE": {1}},
+		},
+		{
+			name:      "default",
+			content:   "port: ${PORT:-8080}",
+			wantVars:  []string{"PORT"},
+			wantLines: map[string][]int{"PORT": {1}},
+		},
+		{
+			name:      "bare",
+			content:   "cmd: run $WORKERS",
+			wantVars:  []string{"WORKERS"},
+			wantLines: map[string][]int{"WORKERS": {1}},
+		},
+		{
+			name:      "all forms of one variable count once per line",
+			content:   "a: ${HOST} ${HOST:-x} $HOST",
+			wantVars:  []string{"HOST"},
+			wantLines: map[string][]int{"HOST": {1}},
+		},
+		{
+			name:      "repeated across lines",
+			content:   "a: ${HOST}\nb: x\nc: $HOST",
+			wantVars:  []string{"HOST"},
+			wantLines: map[string][]int{"HOST": {1, 3}},
+		},
+		{
+			name:      "escaped dollars",
+			content:   "cmd: echo $$HOME $${LITERAL} $$$$NOPE ${REAL}",
+			wantVars:  []string{"REAL"},
+			wantLines: map[string][]int{"REAL": {1}},
+		},
+		{
+			name:      "nested default",
+			content:   "url: ${URL:-${SCHEME}://${HOST}}",
+			wantVars:  []string{"HOST", "SCHEME", "URL"},
+			wantLines: map[string][]int{"HOST": {1}, "SCHEME": {1}, "URL": {1}},
+		},
+		{
+			name:      "docker internal variables",
+			content:   "a: ${COMPOSE_PROJECT_NAME} $HOME ${APP}",
+			wantVars:  []string{"APP"},
+			wantLines: map[string][]int{"APP": {1}},
+		},
+		{
+			name:      "lowercase and unterminated",
+			content:   "a: $lower ${UNTERMINATED",
+			wantVars:  []string{},
+			wantLines: map[string][]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, lines := extractVariableReferences(tt.content)
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", vars, tt.wantVars)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+// threeRegexReferences is the extraction extractVariableReferences replaced:
+// three regexes, each scanning the whole content. It is kept as the
+// benchmark baseline.
+func threeRegexReferences(content string) []string {
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`\$\{([A-Z_][A-Z0-9_]*)\}`),
+		regexp.MustCompile(`\$\{([A-Z_][A-Z0-9_]*):?[^}]*\}`),
+		regexp.MustCompile(`\$([A-Z_][A-Z0-9_]*)`),
+	}
+
+	varSet := make(map[string]bool)
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+			if !isDockerInternalVar(match[1]) {
+				varSet[match[1]] = true
+			}
+		}
+	}
+
+	vars := make([]string, 0, len(varSet))
+	for varName := range varSet {
+		vars = append(vars, varName)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// largeCompose generates a compose file of services with many references
+// and YAML anchors
+func largeCompose(services, vars int) string {
+	var b strings.Builder
+	b.WriteString("x-common: &common\n  environment:\n    LOG_LEVEL: ${LOG_LEVEL:-info}\n")
+	b.WriteString("services:\n")
+	for s := 0; s < services; s++ {
+		fmt.Fprintf(&b, "  svc%d:\n    <<: *common\n    image: ${REGISTRY}/svc%d:${TAG:-latest}\n    environment:\n", s, s)
+		for v := 0; v < vars; v++ {
+			fmt.Fprintf(&b, "      VAR_%d: ${VAR_%d:-default} $$ESCAPED $SHARED_%d\n", v, v, v%10)
+		}
+	}
+	return b.String()
+}
+
+func BenchmarkExtractVariableReferences(b *testing.B) {
+	content := largeCompose(50, 40)
+
+	b.Run("single pass", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		for i := 0; i < b.N; i++ {
+			extractVariableReferences(content)
+		}
+	})
+
+	b.Run("three regexes", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		for i := 0; i < b.N; i++ {
+			threeRegexReferences(content)
+		}
+	})
+}
+
+func TestComposeParameterExpansions(t *testing.T) {
+	info, err := ParseComposeFile("testdata/compose-expansions.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"BINARY", "COMMIT_SHA", "FILE", "HOST", "LIST", "NAME", "SCHEME", "SECRET", "TAG", "URL", "X"}
+	if !reflect.DeepEqual(info.VariableRefs, want) {
+		t.Errorf("VariableRefs = %v, want %v", info.VariableRefs, want)
+	}
+
+	tests := []struct {
+		expansion string
+		want      []string
+	}{
+		{"${PATH##*/}", []string{}},
+		{"${BINARY##*/}", []string{"BINARY"}},
+		{"${NAME:-default}", []string{"NAME"}},
+		{"${X/,/;}", []string{"X"}},
+		{"${LIST//a/b}", []string{"LIST"}},
+		{"${COMMIT_SHA:0:8}", []string{"COMMIT_SHA"}},
+		{"${#SECRET}", []string{"SECRET"}},
+		{"${FILE%.tar.gz}", []string{"FILE"}},
+		{"${A/x/$B}", []string{"A", "B"}},
+		{"${URL:-${SCHEME}://${HOST}}", []string{"HOST", "SCHEME", "URL"}},
+		{"$${NOT_A_REF}", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expansion, func(t *testing.T) {
+			got, _ := extractVariableReferences(tt.expansion)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("references = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
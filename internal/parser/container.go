@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errDockerObjectNotFound is returned by dockerInspectEnv when the object does not exist
+var errDockerObjectNotFound = errors.New("not found")
+
+// ParseImageEnv reads the ENV entries baked into an image via docker image
+// inspect. An image that is not present locally is pulled first when pull is
+// set, and is an error otherwise.
+func ParseImageEnv(image string, pull bool) (EnvVars, error) {
+	entries, err := dockerInspectEnv("image", image)
+	if errors.Is(err, errDockerObjectNotFound) {
+		if !pull {
+			return nil, fmt.Errorf("%w locally (pull it first, or use --pull)", err)
+		}
+
+		cmd := exec.Command("docker", "pull", "--quiet", image)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to pull image %s: %s", image, strings.TrimSpace(stderr.String()))
+		}
+
+		entries, err = dockerInspectEnv("image", image)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseEnvList(entries), nil
+}
+
+// ParseContainerEnv reads a container's configured environment via docker inspect
+func ParseContainerEnv(container string) (EnvVars, error) {
+	entries, err := dockerInspectEnv("container", container)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEnvList(entries), nil
+}
+
+// ParseEnvList parses KEY=VALUE entries such as Docker's Config.Env list
+func ParseEnvList(entries []string) EnvVars {
+	vars := make(EnvVars)
+	for _, entry := range entries {
+		key, value := parseEnvString(entry)
+		if key != "" {
+			vars[key] = value
+		}
+	}
+	return vars
+}
+
+// dockerInspectEnv returns the Config.Env list of a docker object
+func dockerInspectEnv(kind, name string) ([]string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("docker is not available: %w", err)
+	}
+
+	cmd := exec.Command("docker", kind, "inspect", "--format", "{{json .Config.Env}}", name)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		switch {
+		case strings.Contains(msg, "No such"):
+			return nil, fmt.Errorf("%s %s %w", kind, name, errDockerObjectNotFound)
+		case strings.Contains(msg, "Cannot connect to the Docker daemon"):
+			return nil, fmt.Errorf("docker daemon is not reachable: %s", msg)
+		case errors.As(err, &exitErr) && msg != "":
+			return nil, fmt.Errorf("docker %s inspect failed: %s", kind, msg)
+		default:
+			return nil, fmt.Errorf("docker %s inspect failed: %w", kind, err)
+		}
+	}
+
+	var entries []string
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode %s environment: %w", kind, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,8 @@
+// Package parser reads env files, compose files, Dockerfiles and the other
+// sources EnvQuack compares.
+//
+// Like package checker, it never calls os.Exit, log.Fatal or log.Panic, nor
+// writes to stdout or stderr: every problem is returned as an error, or a
+// warning such as DockerfileEnvInfo.Warnings, for the caller to handle.
+// TestNoProcessSideEffects enforces this.
+package parser
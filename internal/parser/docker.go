@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -14,13 +15,18 @@ type DockerfileEnvInfo struct {
 	EnvVars      EnvVars  // ENV instructions
 	ArgVars      EnvVars  // ARG instructions
 	VariableRefs []string // Variables referenced as ${VAR} or $VAR
+	Warnings     []string // Instructions that could not be parsed and were skipped, as "line N: reason"
+
+	Stages    []string            // Build stages in order: the FROM ... AS name, or the stage index
+	VarStages map[string][]string // Stages each variable is declared or referenced in; ARGs before the first FROM have none
 }
 
 // Dockerfile instruction patterns
 var (
-	envInstructionRegex = regexp.MustCompile(`^ENV\s+(.+)$`)
-	argInstructionRegex = regexp.MustCompile(`^ARG\s+(.+)$`)
-	varRefRegex         = regexp.MustCompile(`\$\{?([A-Z_][A-Z0-9_]*)\}?`)
+	fromInstructionRegex = regexp.MustCompile(`^FROM\s+`)
+	envInstructionRegex  = regexp.MustCompile(`^ENV\s+(.+)$`)
+	argInstructionRegex  = regexp.MustCompile(`^ARG\s+(.+)$`)
+	varRefRegex          = regexp.MustCompile(`\$\{?([A-Z_][A-Z0-9_]*)\}?`)
 )
 
 // ParseDockerfile parses a Dockerfile and extracts environment variables
@@ -35,6 +41,9 @@ func ParseDockerfile(filename string) (*DockerfileEnvInfo, error) {
 		EnvVars:      make(EnvVars),
 		ArgVars:      make(EnvVars),
 		VariableRefs: []string{},
+		Warnings:     []string{},
+		Stages:       []string{},
+		VarStages:    make(map[string][]string),
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -63,10 +72,15 @@ func ParseDockerfile(filename string) (*DockerfileEnvInfo, error) {
 			currentInstruction.Reset()
 		}
 
+		// A FROM starts a new build stage
+		if fromInstructionRegex.MatchString(strings.ToUpper(line)) {
+			info.Stages = append(info.Stages, stageName(line, len(info.Stages)))
+		}
+
 		// Parse the instruction
 		if err := parseDockerfileInstruction(line, info); err != nil {
-			// Log warning but continue parsing
-			fmt.Printf("Warning: line %d - %v\n", lineNum, err)
+			// Keep parsing; the caller decides how to surface the warning
+			info.Warnings = append(info.Warnings, fmt.Sprintf("line %d: %v", lineNum, err))
 		}
 	}
 
@@ -82,24 +96,65 @@ func ParseDockerfile(filename string) (*DockerfileEnvInfo, error) {
 	return info, nil
 }
 
-// parseDockerfileInstruction parses a single Dockerfile instruction
+// stageName returns the name a FROM instruction gives its stage (FROM image
+// AS name), or the stage index docker uses for unnamed stages
+func stageName(line string, index int) string {
+	fields := strings.Fields(line)
+	for i := 1; i+1 < len(fields); i++ {
+		if strings.EqualFold(fields[i], "AS") {
+			return fields[i+1]
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+// parseDockerfileInstruction parses a single Dockerfile instruction,
+// attributing its variables to the current build stage
 func parseDockerfileInstruction(line string, info *DockerfileEnvInfo) error {
 	line = strings.TrimSpace(line)
 	upperLine := strings.ToUpper(line)
 
-	// Parse ENV instructions
+	declared := make(EnvVars)
+	var err error
+
 	if envMatch := envInstructionRegex.FindStringSubmatch(upperLine); envMatch != nil {
+		// Parse ENV instructions
 		envContent := strings.TrimSpace(line[4:]) // Remove "ENV " prefix from original line
-		return parseEnvInstruction(envContent, info.EnvVars)
+		err = parseEnvInstruction(envContent, declared)
+		for key, value := range declared {
+			info.EnvVars[key] = value
+		}
+	} else if argMatch := argInstructionRegex.FindStringSubmatch(upperLine); argMatch != nil {
+		// Parse ARG instructions
+		argContent := strings.TrimSpace(line[4:]) // Remove "ARG " prefix from original line
+		err = parseArgInstruction(argContent, declared)
+		for key, value := range declared {
+			info.ArgVars[key] = value
+		}
 	}
 
-	// Parse ARG instructions
-	if argMatch := argInstructionRegex.FindStringSubmatch(upperLine); argMatch != nil {
-		argContent := strings.TrimSpace(line[4:]) // Remove "ARG " prefix from original line
-		return parseArgInstruction(argContent, info.ArgVars)
+	if len(info.Stages) > 0 {
+		stage := info.Stages[len(info.Stages)-1]
+		for key := range declared {
+			info.addVarStage(key, stage)
+		}
+		for _, match := range varRefRegex.FindAllStringSubmatch(line, -1) {
+			if !isSystemVar(match[1]) {
+				info.addVarStage(match[1], stage)
+			}
+		}
 	}
 
-	return nil
+	return err
+}
+
+// addVarStage records that a variable appears in a stage
+func (d *DockerfileEnvInfo) addVarStage(key, stage string) {
+	stages := d.VarStages[key]
+	if len(stages) > 0 && stages[len(stages)-1] == stage {
+		return
+	}
+	d.VarStages[key] = append(stages, stage)
 }
 
 // parseEnvInstruction parses ENV instruction content
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// DefaultEncoding is how env files are read unless told otherwise
+const DefaultEncoding = "utf8"
+
+// encodings are the file encodings env files can be decoded from. UTF-16
+// honours a byte order mark and otherwise assumes the named endianness.
+var encodings = map[string]encoding.Encoding{
+	"utf8":         unicode.UTF8,
+	"latin1":       charmap.ISO8859_1,
+	"latin9":       charmap.ISO8859_15,
+	"windows-1252": charmap.Windows1252,
+	"utf16le":      unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf16be":      unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+}
+
+// LookupEncoding checks an encoding name, accepting common spellings such as
+// UTF-8, ISO-8859-1 or cp1252
+func LookupEncoding(name string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+	switch normalized {
+	case "", "utf8":
+		return "utf8", nil
+	case "latin1", "iso88591":
+		return "latin1", nil
+	case "latin9", "iso885915":
+		return "latin9", nil
+	case "windows1252", "cp1252":
+		return "windows-1252", nil
+	case "utf16le", "utf16":
+		return "utf16le", nil
+	case "utf16be":
+		return "utf16be", nil
+	}
+
+	names := make([]string, 0, len(encodings))
+	for n := range encodings {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown encoding %q (use %s)", name, strings.Join(names, ", "))
+}
+
+// decodeReader wraps r to decode the named encoding into UTF-8
+func decodeReader(r io.Reader, name string) (io.Reader, error) {
+	canonical, err := LookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	if canonical == "utf8" {
+		return r, nil
+	}
+	return transform.NewReader(r, encodings[canonical].NewDecoder()), nil
+}
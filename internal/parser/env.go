@@ -2,53 +2,465 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
 // EnvVars represents a collection of environment variables
 type EnvVars map[string]string
 
+// valueRefRegex matches ${VAR}, ${VAR:-default} and $VAR references inside values
+var valueRefRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)[^}]*\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// commentedAssignmentRegex matches a commented-out assignment such as
+// `# OPTIONAL_URL=` once the # is stripped; prose never has a bare
+// identifier directly followed by =
+var commentedAssignmentRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+
+// EnvEntry is a single assignment read from an env file
+type EnvEntry struct {
+	Key         string
+	Value       string
+	Line        int         // 1-based line number of the assignment
+	EndLine     int         // 1-based line the value ends on, after Line for a quoted value spanning lines
+	Quote       byte        // Quote character around the value, 0 if unquoted
+	Comment     string      // Trailing comment after the value, without the #
+	Doc         []string    // Comment lines directly above the entry
+	Annotations Annotations // @annotations from the comments directly above
+}
+
+// Comment is a full-line comment of an env file
+type Comment struct {
+	Line int    // 1-based line number
+	Text string // Comment text without the leading # and surrounding spaces
+}
+
+// ParsedFile is an env file parsed in declaration order
+type ParsedFile struct {
+	Filename  string
+	Entries   []EnvEntry
+	Commented []EnvEntry // Commented-out assignments, with ParseOptions.CommentedKeys
+	Comments  []Comment  // Every full-line comment, in file order
+}
+
+// ParseOptions controls how env files are read
+type ParseOptions struct {
+	INI    bool // Treat [section] headers as key prefixes: host under [db] becomes DB_HOST
+	Docker bool // Read like docker run --env-file (see parseDockerEnvLine)
+
+	// Encoding the file is decoded from, e.g. latin1 or utf16le (see
+	// LookupEncoding); empty means UTF-8
+	Encoding string
+
+	// Capture commented-out assignments like `# OPTIONAL_URL=` in
+	// ParsedFile.Commented instead of treating them as documentation
+	CommentedKeys bool
+}
+
+// DefaultParseOptions returns plain dotenv parsing
+func DefaultParseOptions() *ParseOptions {
+	return &ParseOptions{}
+}
+
 // ParseEnvFile parses a .env file and returns the environment variables
 func ParseEnvFile(filename string) (EnvVars, error) {
+	parsed, err := ParseEnvFileOrdered(filename)
+	if parsed == nil {
+		return nil, err
+	}
+	return parsed.EnvVars(), err
+}
+
+// ParseEnvFileOrdered parses a .env file keeping entry order, line numbers and quoting
+func ParseEnvFileOrdered(filename string) (*ParsedFile, error) {
+	return ParseEnvFileWithOptions(filename, nil)
+}
+
+// ParseEnvFileWithOptions parses an env file like ParseEnvFileOrdered using opts
+func ParseEnvFileWithOptions(filename string, opts *ParseOptions) (*ParsedFile, error) {
+	// Files at a git ref are read through git instead of the working tree
+	if IsGitSource(filename) {
+		data, err := ReadGitSource(filename)
+		if err != nil {
+			return nil, err
+		}
+		return ParseEnvReader(bytes.NewReader(data), filename, opts)
+	}
+
+	// http(s) sources are downloaded within the remote limits
+	if IsRemoteSource(filename) {
+		data, err := ReadRemoteSource(filename)
+		if err != nil {
+			return nil, err
+		}
+		return ParseEnvReader(bytes.NewReader(data), filename, opts)
+	}
+
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	vars := make(EnvVars)
-	scanner := bufio.NewScanner(file)
+	return ParseEnvReader(file, filename, opts)
+}
+
+// ParseEnvReader parses env file content from r; filename is used for
+// messages and ParsedFile.Filename
+func ParseEnvReader(r io.Reader, filename string, opts *ParseOptions) (*ParsedFile, error) {
+	if opts == nil {
+		opts = DefaultParseOptions()
+	}
+
+	r, err := decodeReader(r, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &ParsedFile{Filename: filename}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	// Comment block directly above the next entry
+	var doc []string
+	annotations := make(Annotations)
+
+	// Current INI section prefix
+	section := ""
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+		raw := scanner.Text()
+		if lineNum == 1 {
+			// A byte order mark is not part of the first key (Docker strips it too)
+			raw = strings.TrimPrefix(raw, "\uFEFF")
+		}
+		line := strings.TrimSpace(raw)
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
+		// A blank line detaches any preceding comments
+		if line == "" {
+			doc = nil
+			annotations = make(Annotations)
+			continue
+		}
+
+		// INI section headers prefix the keys that follow
+		if opts.INI && strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = iniSectionPrefix(line[1 : len(line)-1])
+			doc = nil
+			annotations = make(Annotations)
+			continue
+		}
+
+		// Collect comments as documentation for the next entry
+		if strings.HasPrefix(line, "#") || (opts.INI && strings.HasPrefix(line, ";")) {
+			text := strings.TrimSpace(line[1:])
+			parsed.Comments = append(parsed.Comments, Comment{Line: lineNum, Text: text})
+			if m := commentedAssignmentRegex.FindStringSubmatch(text); m != nil && opts.CommentedKeys {
+				parsed.Commented = append(parsed.Commented, EnvEntry{
+					Key:     m[1],
+					Value:   m[2],
+					Line:    lineNum,
+					EndLine: lineNum,
+					Doc:     doc,
+				})
+				doc = nil
+				annotations = make(Annotations)
+				continue
+			}
+			if isAnnotationComment(text) {
+				parseAnnotationComment(text, annotations)
+			} else {
+				doc = append(doc, text)
+			}
+			continue
+		}
+
+		// Docker has its own, much simpler, rules for assignments
+		if opts.Docker {
+			entry, ok, err := parseDockerEnvLine(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filename, lineNum, err)
+			}
+			if ok {
+				entry.Line, entry.EndLine = lineNum, lineNum
+				entry.Doc = doc
+				entry.Annotations = annotations
+				parsed.Entries = append(parsed.Entries, entry)
+			}
+			doc = nil
+			annotations = make(Annotations)
 			continue
 		}
 
-		// Split on first = sign
-		parts := strings.SplitN(line, "=", 2)
+		// Split on first = sign, past a shell `export ` so the file can be sourced
+		parts := strings.SplitN(stripExport(line), "=", 2)
 		if len(parts) != 2 {
+			doc = nil
+			annotations = make(Annotations)
 			continue
 		}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+		entry := EnvEntry{
+			Key:         strings.TrimSpace(parts[0]),
+			Value:       strings.TrimSpace(parts[1]),
+			Line:        lineNum,
+			EndLine:     lineNum,
+			Doc:         doc,
+			Annotations: annotations,
+		}
+		doc = nil
+		annotations = make(Annotations)
+
+		// A quote left open continues on the following lines, e.g. a PEM key
+		quote, open := openQuote(entry.Value)
+		if !open {
+			entry.Value, entry.Comment = splitInlineComment(entry.Value)
+		} else {
+			var value strings.Builder
+			value.WriteString(entry.Value)
+			closed := false
+			for !closed && scanner.Scan() {
+				lineNum++
+				next := scanner.Text()
+				// Anything after the closing quote, like a comment, is dropped
+				if end := closingQuote(next, quote); end >= 0 {
+					entry.Comment = trailingComment(next[end+1:])
+					next, closed = next[:end+1], true
+				}
+				value.WriteString("\n" + next)
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			if !closed {
+				return nil, fmt.Errorf("%s:%d: unterminated %s value for %s", filename, entry.Line, quoteStyle(quote), entry.Key)
+			}
+			entry.Value = value.String()
+			entry.EndLine = lineNum
+		}
+
+		if section != "" {
+			entry.Key = section + "_" + strings.ToUpper(entry.Key)
+		}
 
 		// Remove quotes if present
-		if len(value) >= 2 {
-			if (strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"")) ||
-				(strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")) {
-				value = value[1 : len(value)-1]
+		if len(entry.Value) >= 2 {
+			if (strings.HasPrefix(entry.Value, "\"") && strings.HasSuffix(entry.Value, "\"")) ||
+				(strings.HasPrefix(entry.Value, "'") && strings.HasSuffix(entry.Value, "'")) {
+				entry.Quote = entry.Value[0]
+				entry.Value = entry.Value[1 : len(entry.Value)-1]
+				if entry.Quote == '"' {
+					entry.Value = unescapeDoubleQuoted(entry.Value)
+				}
 			}
 		}
 
-		vars[key] = value
+		parsed.Entries = append(parsed.Entries, entry)
 	}
 
-	return vars, scanner.Err()
+	return parsed, scanner.Err()
+}
+
+// splitInlineComment separates a trailing comment from a single-line value.
+// In an unquoted value a # starts a comment at the beginning or after
+// whitespace, so PORT=8080#x keeps its #; a quoted value keeps every # inside
+// the quotes and may only be followed by a comment.
+func splitInlineComment(value string) (string, string) {
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		end := closingQuote(value[1:], value[0]) + 1
+		rest := strings.TrimSpace(value[end+1:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return value, ""
+		}
+		return value[:end+1], trailingComment(rest)
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+		}
+	}
+	return value, ""
+}
+
+// trailingComment returns the text of a # comment following a value, or ""
+func trailingComment(rest string) string {
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "#") {
+		return ""
+	}
+	return strings.TrimSpace(rest[1:])
+}
+
+// doubleQuoteEscapes are the backslash escapes decoded inside double quotes
+var doubleQuoteEscapes = map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '"': "\""}
+
+// unescapeDoubleQuoted decodes \n, \t, \r, \\ and \" in a double-quoted
+// value, as dotenv libraries and docker compose do. Any other backslash is
+// kept literally. Single-quoted values are never unescaped.
+func unescapeDoubleQuoted(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			if decoded, ok := doubleQuoteEscapes[value[i+1]]; ok {
+				out.WriteString(decoded)
+				i++
+				continue
+			}
+		}
+		out.WriteByte(value[i])
+	}
+	return out.String()
+}
+
+// stripExport removes a leading `export` keyword from an assignment. Only the
+// literal word followed by whitespace counts, so exportFOO=1 keeps its key.
+func stripExport(line string) string {
+	rest, ok := strings.CutPrefix(line, "export")
+	if !ok || rest == "" || !unicode.IsSpace(rune(rest[0])) {
+		return line
+	}
+	return strings.TrimLeftFunc(rest, unicode.IsSpace)
+}
+
+// openQuote reports whether value opens a quote it does not close on the
+// same line, returning the quote character
+func openQuote(value string) (byte, bool) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return 0, false
+	}
+	quote := value[0]
+	return quote, closingQuote(value[1:], quote) < 0
+}
+
+// closingQuote returns the index of the closing quote in text, or -1; inside
+// double quotes, a backslash-escaped \" does not close
+func closingQuote(text string, quote byte) int {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && quote == '"':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// quoteStyle names a quote character for messages
+func quoteStyle(quote byte) string {
+	if quote == '\'' {
+		return "single-quoted"
+	}
+	return "double-quoted"
+}
+
+// parseDockerEnvLine parses an assignment exactly like docker run --env-file:
+// only leading whitespace is trimmed, everything after the first = is the
+// value verbatim (quotes and # included), and a bare KEY takes its value from
+// the host environment or is skipped when the host doesn't set it.
+func parseDockerEnvLine(raw string) (EnvEntry, bool, error) {
+	line := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	key, value, hasValue := strings.Cut(line, "=")
+
+	if key == "" {
+		return EnvEntry{}, false, fmt.Errorf("no variable name in %q", line)
+	}
+	if strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+		return EnvEntry{}, false, fmt.Errorf("variable %q contains whitespace", key)
+	}
+
+	if !hasValue {
+		hostValue, exists := os.LookupEnv(key)
+		if !exists {
+			return EnvEntry{}, false, nil
+		}
+		value = hostValue
+	}
+
+	return EnvEntry{Key: key, Value: value}, true, nil
+}
+
+// iniSectionPrefix turns an INI section name into an env key prefix,
+// e.g. "redis.cache" becomes "REDIS_CACHE"
+func iniSectionPrefix(name string) string {
+	prefix := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	return strings.ToUpper(prefix)
+}
+
+// Lookup returns the last entry for key
+func (p *ParsedFile) Lookup(key string) (EnvEntry, bool) {
+	for i := len(p.Entries) - 1; i >= 0; i-- {
+		if p.Entries[i].Key == key {
+			return p.Entries[i], true
+		}
+	}
+	return EnvEntry{}, false
+}
+
+// MapKeys rewrites every entry's key with fn, commented-out ones included
+func (p *ParsedFile) MapKeys(fn func(key string) string) {
+	for i := range p.Entries {
+		p.Entries[i].Key = fn(p.Entries[i].Key)
+	}
+	for i := range p.Commented {
+		p.Commented[i].Key = fn(p.Commented[i].Key)
+	}
+}
+
+// Keys returns the keys in order of first declaration
+func (p *ParsedFile) Keys() []string {
+	keys := []string{}
+	seen := make(map[string]bool)
+	for _, entry := range p.Entries {
+		if !seen[entry.Key] {
+			seen[entry.Key] = true
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// CommentedKeys returns the keys of commented-out assignments that are not
+// also set by a real entry
+func (p *ParsedFile) CommentedKeys() []string {
+	set := make(map[string]bool)
+	for _, entry := range p.Entries {
+		set[entry.Key] = true
+	}
+
+	keys := []string{}
+	for _, entry := range p.Commented {
+		if !set[entry.Key] {
+			set[entry.Key] = true
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// EnvVars returns the entries as EnvVars, later duplicates overriding earlier ones
+func (p *ParsedFile) EnvVars() EnvVars {
+	vars := make(EnvVars)
+	for _, entry := range p.Entries {
+		vars[entry.Key] = entry.Value
+	}
+	return vars
 }
 
 // GetKeys returns all the keys from the environment variables
@@ -65,3 +477,25 @@ func (e EnvVars) Has(key string) bool {
 	_, exists := e[key]
 	return exists
 }
+
+// refDefault returns the fallback of a ${VAR:-default} reference
+func refDefault(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "${") {
+		return "", false
+	}
+	_, fallback, ok := strings.Cut(strings.TrimSuffix(ref, "}"), ":-")
+	return fallback, ok
+}
+
+// ExtractValueRefs returns the variable names referenced in a value, in order of appearance
+func ExtractValueRefs(value string) []string {
+	var refs []string
+	for _, match := range valueRefRegex.FindAllStringSubmatch(value, -1) {
+		if match[1] != "" {
+			refs = append(refs, match[1])
+		} else {
+			refs = append(refs, match[2])
+		}
+	}
+	return refs
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseEntry parses a single env line and returns its only entry
+func parseEntry(t *testing.T, line string) EnvEntry {
+	t.Helper()
+	parsed, err := ParseEnvReader(strings.NewReader(line+"\n"), ".env", nil)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", line, err)
+	}
+	if len(parsed.Entries) != 1 {
+		t.Fatalf("parsing %q gave %d entries, want 1", line, len(parsed.Entries))
+	}
+	return parsed.Entries[0]
+}
+
+func TestParseExportPrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		key   string
+		value string
+	}{
+		{"export", "export DATABASE_URL=foo", "DATABASE_URL", "foo"},
+		{"export with tab", "export\tDATABASE_URL=foo", "DATABASE_URL", "foo"},
+		{"several spaces", "export    DATABASE_URL=foo", "DATABASE_URL", "foo"},
+		{"indented", "    export DATABASE_URL=foo", "DATABASE_URL", "foo"},
+		{"indented with tab", "\texport DATABASE_URL=foo", "DATABASE_URL", "foo"},
+		{"quoted value", `export TOKEN="a b"`, "TOKEN", "a b"},
+		{"no space", "exportFOO=1", "exportFOO", "1"},
+		{"key named export", "export=1", "export", "1"},
+		{"key starting with export", "EXPORT_DIR=/tmp", "EXPORT_DIR", "/tmp"},
+		{"value with export", "CMD=export FOO", "CMD", "export FOO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := parseEntry(t, tt.line)
+			if entry.Key != tt.key || entry.Value != tt.value {
+				t.Errorf("%q parsed as %q=%q, want %q=%q", tt.line, entry.Key, entry.Value, tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseInlineComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		value   string
+		comment string
+	}{
+		{"unquoted", "PORT=8080 # default port", "8080", "default port"},
+		{"tab before #", "PORT=8080\t# default port", "8080", "default port"},
+		{"no space before #", "PORT=8080#x", "8080#x", ""},
+		{"# first in value", "PORT=# set me", "", "set me"},
+		{"# first after spaces", "PORT=   # set me", "", "set me"},
+		{"bare #", "PORT=#", "", ""},
+		{"several #", "URL=a#b # c # d", "a#b", "c # d"},
+		{"double quoted #", `TOKEN="a#b"`, "a#b", ""},
+		{"double quoted spaced #", `TOKEN="a # b"`, "a # b", ""},
+		{"double quoted then comment", `TOKEN="a#b" # the token`, "a#b", "the token"},
+		{"single quoted #", "TOKEN='a # b'", "a # b", ""},
+		{"single quoted then comment", "TOKEN='a#b' #x", "a#b", "x"},
+		{"escaped quote before #", `TOKEN="a\" # b"`, `a" # b`, ""},
+		{"no comment", "NAME=value", "value", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := parseEntry(t, tt.line)
+			if entry.Value != tt.value || entry.Comment != tt.comment {
+				t.Errorf("%q parsed as value %q, comment %q, want %q, %q", tt.line, entry.Value, entry.Comment, tt.value, tt.comment)
+			}
+		})
+	}
+}
+
+func TestParseEscapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		value string
+	}{
+		{"double newline", `MSG="a\nb"`, "a\nb"},
+		{"double tab", `MSG="a\tb"`, "a\tb"},
+		{"double carriage return", `MSG="a\rb"`, "a\rb"},
+		{"double backslash", `MSG="a\\b"`, `a\b`},
+		{"double quote", `MSG="say \"hi\""`, `say "hi"`},
+		{"double escaped backslash before n", `MSG="a\\nb"`, `a\nb`},
+		{"double unknown escape", `MSG="a\qb"`, `a\qb`},
+		{"double trailing backslash", `MSG="a\\"`, `a\`},
+		{"single newline", `MSG='a\nb'`, `a\nb`},
+		{"single tab", `MSG='a\tb'`, `a\tb`},
+		{"single backslash", `MSG='a\\b'`, `a\\b`},
+		{"single double quote", `MSG='say \"hi\"'`, `say \"hi\"`},
+		{"unquoted", `MSG=a\nb`, `a\nb`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if entry := parseEntry(t, tt.line); entry.Value != tt.value {
+				t.Errorf("%s parsed as %q, want %q", tt.line, entry.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseEscapedMultilineValue(t *testing.T) {
+	content := "KEY=\"-----BEGIN KEY-----\\nabc\nline \\\"two\\\"\n-----END KEY-----\"\nSINGLE='x\\n\ny'\n"
+	parsed, err := ParseEnvReader(strings.NewReader(content), ".env", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := parsed.EnvVars()
+	if want := "-----BEGIN KEY-----\nabc\nline \"two\"\n-----END KEY-----"; vars["KEY"] != want {
+		t.Errorf("KEY = %q, want %q", vars["KEY"], want)
+	}
+	if want := "x\\n\ny"; vars["SINGLE"] != want {
+		t.Errorf("SINGLE = %q, want %q", vars["SINGLE"], want)
+	}
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnresolvedRef is a key whose value references variables that are neither
+// set in the file nor provided by the lookup, and have no default
+type UnresolvedRef struct {
+	Key  string
+	Refs []string // Referenced names, in order of appearance
+}
+
+// CycleError reports variables whose values reference each other
+type CycleError struct {
+	File  string
+	Cycle []string // Keys along the cycle, ending with the first one again
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%s: circular reference: %s", e.File, strings.Join(e.Cycle, " -> "))
+}
+
+// ParseEnvFileExpanded parses an env file and expands the ${VAR} references
+// in its values (see ExpandEnvVars)
+func ParseEnvFileExpanded(filename string, opts *ParseOptions, lookup func(string) (string, bool)) (EnvVars, []UnresolvedRef, error) {
+	parsed, err := ParseEnvFileWithOptions(filename, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ExpandEnvVars(parsed, lookup)
+}
+
+// ExpandEnvVars returns the variables of parsed with ${VAR}, ${VAR:-default}
+// and $VAR references in their values expanded. A reference resolves to the
+// key of the same name in the file, else through lookup (usually
+// os.LookupEnv, nil to disable), else to its default, which ${VAR:-default}
+// also uses for an empty value. References that resolve nowhere expand to
+// an empty string, as in a shell, and are returned per key. Single-quoted
+// values are literal. Values referencing each other give a *CycleError.
+func ExpandEnvVars(parsed *ParsedFile, lookup func(string) (string, bool)) (EnvVars, []UnresolvedRef, error) {
+	// The last assignment of a key wins, and decides whether it is literal
+	literal := make(map[string]bool)
+	for _, entry := range parsed.Entries {
+		literal[entry.Key] = entry.Quote == '\''
+	}
+	return expandVars(parsed.EnvVars(), parsed.Keys(), literal, lookup, parsed.Filename)
+}
+
+// ExpandVars expands the references in vars by the rules of ExpandEnvVars,
+// for variables that don't come with quoting, e.g. after merging or renaming
+// keys. source names them in a *CycleError.
+func ExpandVars(vars EnvVars, source string, lookup func(string) (string, bool)) (EnvVars, []UnresolvedRef, error) {
+	keys := vars.GetKeys()
+	sort.Strings(keys)
+	return expandVars(vars, keys, nil, lookup, source)
+}
+
+// expandVars expands every key of vars in keys order, so the first cycle
+// found is the same on every run
+func expandVars(vars EnvVars, keys []string, literal map[string]bool, lookup func(string) (string, bool), source string) (EnvVars, []UnresolvedRef, error) {
+	e := &expander{
+		raw:        vars,
+		literal:    literal,
+		lookup:     lookup,
+		file:       source,
+		expanded:   make(EnvVars),
+		unresolved: make(map[string][]string),
+		visiting:   make(map[string]bool),
+	}
+
+	for _, key := range keys {
+		if _, err := e.expand(key, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	unresolved := make([]UnresolvedRef, 0, len(e.unresolved))
+	for key, refs := range e.unresolved {
+		unresolved = append(unresolved, UnresolvedRef{Key: key, Refs: refs})
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+		return unresolved[i].Key < unresolved[j].Key
+	})
+
+	return e.expanded, unresolved, nil
+}
+
+// expander resolves the values of one file, memoizing expanded keys
+type expander struct {
+	raw        EnvVars
+	literal    map[string]bool // Keys whose values are kept as written, may be nil
+	lookup     func(string) (string, bool)
+	file       string
+	expanded   EnvVars
+	unresolved map[string][]string
+	visiting   map[string]bool
+}
+
+// expand returns the expanded value of key; path is the chain of keys
+// being expanded that led here
+func (e *expander) expand(key string, path []string) (string, error) {
+	if value, done := e.expanded[key]; done {
+		return value, nil
+	}
+
+	path = append(path, key)
+	if e.visiting[key] {
+		start := 0
+		for path[start] != key {
+			start++
+		}
+		return "", &CycleError{File: e.file, Cycle: path[start:]}
+	}
+
+	value := e.raw[key]
+	if !e.literal[key] {
+		e.visiting[key] = true
+		var err error
+		value, err = e.substitute(key, value, path)
+		delete(e.visiting, key)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	e.expanded[key] = value
+	return value, nil
+}
+
+// substitute expands the references in value, which belongs to key
+func (e *expander) substitute(key, value string, path []string) (string, error) {
+	var firstErr error
+	result := valueRefRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		if firstErr != nil {
+			return ref
+		}
+
+		match := valueRefRegex.FindStringSubmatch(ref)
+		name := match[1] + match[2]
+		fallback, hasFallback := refDefault(ref)
+
+		var resolved string
+		found := false
+		if _, inFile := e.raw[name]; inFile {
+			v, err := e.expand(name, path)
+			if err != nil {
+				firstErr = err
+				return ref
+			}
+			resolved, found = v, true
+		} else if e.lookup != nil {
+			resolved, found = e.lookup(name)
+		}
+
+		if found && (resolved != "" || !hasFallback) {
+			return resolved
+		}
+		if hasFallback {
+			v, err := e.substitute(key, fallback, path)
+			if err != nil {
+				firstErr = err
+				return ref
+			}
+			return v
+		}
+
+		e.unresolved[key] = append(e.unresolved[key], name)
+		return ""
+	})
+
+	return result, firstErr
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		cycle   []string
+	}{
+		{"self reference", "A=${A}${A}\n", []string{"A", "A"}},
+		{"bare self reference", "A=x$A\n", []string{"A", "A"}},
+		{"two keys", "A=${B}\nB=${A}\n", []string{"A", "B", "A"}},
+		{"three keys", "A=${B}\nB=${C}\nC=x${A}\n", []string{"A", "B", "C", "A"}},
+		{"through a defaulted reference", "A=${B:-x}\nB=${A}\n", []string{"A", "B", "A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseEnvReader(strings.NewReader(tt.content), ".env", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Parsed files and plain variables follow the same rules
+			_, _, fileErr := ExpandEnvVars(parsed, nil)
+			_, _, varsErr := ExpandVars(parsed.EnvVars(), ".env", nil)
+
+			for name, err := range map[string]error{"ExpandEnvVars": fileErr, "ExpandVars": varsErr} {
+				var cycle *CycleError
+				if !errors.As(err, &cycle) {
+					t.Errorf("%s error = %v, want a *CycleError", name, err)
+					continue
+				}
+				if !reflect.DeepEqual(cycle.Cycle, tt.cycle) {
+					t.Errorf("%s cycle = %v, want %v", name, cycle.Cycle, tt.cycle)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandRules(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "FROM_OS" {
+			return "os", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name       string
+		content    string
+		key        string
+		want       string
+		unresolved []string
+	}{
+		{"braced", "HOST=db\nURL=${HOST}:5432\n", "URL", "db:5432", nil},
+		{"bare", "HOST=db\nURL=$HOST:5432\n", "URL", "db:5432", nil},
+		{"chained", "A=${B}\nB=${C}\nC=c\n", "A", "c", nil},
+		{"repeated", "A=a\nB=${A}${A}${A}\n", "B", "aaa", nil},
+		{"lookup", "A=${FROM_OS}\n", "A", "os", nil},
+		{"default when unset", "A=${UNSET:-fallback}\n", "A", "fallback", nil},
+		{"default when empty", "EMPTY=\nA=${EMPTY:-fallback}\n", "A", "fallback", nil},
+		{"default unused", "SET=x\nA=${SET:-fallback}\n", "A", "x", nil},
+		{"unresolved is empty", "A=<${UNSET}>\n", "A", "<>", []string{"UNSET"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseEnvReader(strings.NewReader(tt.content), ".env", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fileVars, fileUnresolved, err := ExpandEnvVars(parsed, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars, unresolved, err := ExpandVars(parsed.EnvVars(), ".env", lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fileVars[tt.key] != tt.want || vars[tt.key] != tt.want {
+				t.Errorf("%s = %q (file) and %q (vars), want %q", tt.key, fileVars[tt.key], vars[tt.key], tt.want)
+			}
+			if !reflect.DeepEqual(fileUnresolved, unresolved) {
+				t.Errorf("unresolved differ: %v (file) and %v (vars)", fileUnresolved, unresolved)
+			}
+
+			var refs []string
+			for _, u := range unresolved {
+				refs = append(refs, u.Refs...)
+			}
+			if !reflect.DeepEqual(refs, tt.unresolved) {
+				t.Errorf("unresolved = %v, want %v", refs, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestExpandKeepsSingleQuotedValues(t *testing.T) {
+	parsed, err := ParseEnvReader(strings.NewReader("A=a\nB='${A}'\n"), ".env", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars, _, err := ExpandEnvVars(parsed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["B"] != "${A}" {
+		t.Errorf("B = %q, want it literal", vars["B"])
+	}
+}
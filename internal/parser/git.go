@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotInGitRef is returned by ReadGitSource when the path does not exist at the ref
+var ErrNotInGitRef = errors.New("does not exist at git ref")
+
+// gitSourcePrefix marks a file read from a git ref instead of the working tree,
+// e.g. git:main:.env.example
+const gitSourcePrefix = "git:"
+
+// IsGitSource reports whether a file argument names a file at a git ref
+func IsGitSource(source string) bool {
+	return strings.HasPrefix(source, gitSourcePrefix)
+}
+
+// ParseGitSource splits git:<ref>:<path> into its ref and path
+func ParseGitSource(source string) (string, string, error) {
+	ref, path, ok := strings.Cut(strings.TrimPrefix(source, gitSourcePrefix), ":")
+	if !ok || ref == "" || path == "" {
+		return "", "", fmt.Errorf("invalid git source %q (use git:<ref>:<path>)", source)
+	}
+	return ref, path, nil
+}
+
+// GitSource builds the git:<ref>:<path> form of a file at a git ref
+func GitSource(ref, path string) string {
+	return gitSourcePrefix + ref + ":" + path
+}
+
+// VerifyGitRef checks that ref names a git object, via git rev-parse. Refs
+// starting with - are rejected before git sees them, so a ref from the
+// command line or a config file can never be taken for an option.
+func VerifyGitRef(ref string) error {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid git ref %q", ref)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is not available: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "--end-of-options", ref)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			return fmt.Errorf("cannot read git ref %s: not inside a git repository", ref)
+		}
+		return fmt.Errorf("git ref %s not found", ref)
+	}
+	return nil
+}
+
+// ReadGitSource returns the contents of a git:<ref>:<path> file via git show.
+// The path is relative to the repository root. The ref is verified first
+// (see VerifyGitRef), and a file without content is an error, never an
+// empty file.
+func ReadGitSource(source string) ([]byte, error) {
+	ref, path, err := ParseGitSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyGitRef(ref); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "show", "--end-of-options", ref+":"+path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		switch {
+		case strings.Contains(msg, "not a git repository"):
+			return nil, fmt.Errorf("cannot read %s: not inside a git repository", source)
+		case strings.Contains(msg, "does not exist in") || strings.Contains(msg, "exists on disk, but not in"):
+			return nil, fmt.Errorf("%s %w %s", path, ErrNotInGitRef, ref)
+		case strings.Contains(msg, "invalid object name") || strings.Contains(msg, "unknown revision"):
+			return nil, fmt.Errorf("git ref %s not found", ref)
+		case errors.As(err, &exitErr) && msg != "":
+			return nil, fmt.Errorf("git show %s:%s failed: %s", ref, path, msg)
+		default:
+			return nil, fmt.Errorf("git show %s:%s failed: %w", ref, path, err)
+		}
+	}
+
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("git show %s:%s returned nothing", ref, path)
+	}
+
+	return stdout.Bytes(), nil
+}
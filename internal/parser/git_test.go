@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyGitRefRejectsOptions(t *testing.T) {
+	for _, ref := range []string{"", "-", "--output=/tmp/pwned", "-p"} {
+		err := VerifyGitRef(ref)
+		if err == nil || !strings.Contains(err.Error(), "invalid git ref") {
+			t.Errorf("VerifyGitRef(%q) = %v, want an invalid git ref error", ref, err)
+		}
+	}
+}
+
+func TestReadGitSourceRejectsOptionRefs(t *testing.T) {
+	_, err := ReadGitSource("git:--output=/tmp/pwned:x")
+	if err == nil || !strings.Contains(err.Error(), "invalid git ref") {
+		t.Fatalf("ReadGitSource() = %v, want an invalid git ref error", err)
+	}
+}
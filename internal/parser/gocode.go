@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoEnvUsage is a place in Go source that reads an environment variable
+type GoEnvUsage struct {
+	Key  string
+	File string // Relative to the scanned directory
+	Line int
+}
+
+// goEnvFuncs are the os functions whose first argument is a variable name
+var goEnvFuncs = map[string]bool{
+	"Getenv":    true,
+	"LookupEnv": true,
+}
+
+// ScanGoEnvUsages finds os.Getenv and os.LookupEnv calls with a string literal
+// name in the .go files under root, honouring import aliases of "os".
+// Vendored, testdata and hidden directories are skipped. Usages are sorted
+// by file and line.
+func ScanGoEnvUsages(root string) ([]GoEnvUsage, error) {
+	usages := []GoEnvUsage{}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			// Files that don't parse (generated templates, WIP) are skipped
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		for _, usage := range goFileEnvUsages(fset, file) {
+			usage.File = filepath.ToSlash(rel)
+			usages = append(usages, usage)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].File != usages[j].File {
+			return usages[i].File < usages[j].File
+		}
+		return usages[i].Line < usages[j].Line
+	})
+
+	return usages, nil
+}
+
+// goFileEnvUsages returns the env reads in one parsed file
+func goFileEnvUsages(fset *token.FileSet, file *ast.File) []GoEnvUsage {
+	// The name "os" is imported under, if at all
+	osName := ""
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == "os" {
+			osName = "os"
+			if imp.Name != nil {
+				osName = imp.Name.Name
+			}
+		}
+	}
+	if osName == "" || osName == "_" {
+		return nil
+	}
+
+	usages := []GoEnvUsage{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !goEnvFuncs[sel.Sel.Name] {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != osName {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		key, err := strconv.Unquote(lit.Value)
+		if err != nil || key == "" {
+			return true
+		}
+
+		usages = append(usages, GoEnvUsage{Key: key, Line: fset.Position(call.Pos()).Line})
+		return true
+	})
+
+	return usages
+}
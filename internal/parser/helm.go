@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseHelmValues flattens a Helm values.yaml into one key per leaf value,
+// naming each leaf with joinKey applied to its path (map keys, and list
+// indexes as "0", "1", ...). Empty maps, empty lists and nulls are kept as
+// keys with an empty value.
+func ParseHelmValues(filename string, joinKey func(path []string) string) (EnvVars, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Helm values: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	vars := make(EnvVars)
+	if len(root.Content) > 0 {
+		flattenYAMLNode(root.Content[0], nil, joinKey, vars)
+	}
+
+	return vars, nil
+}
+
+// flattenYAMLNode adds the leaves under node to vars
+func flattenYAMLNode(node *yaml.Node, path []string, joinKey func([]string) string, vars EnvVars) {
+	// Copy before appending so sibling paths never share a backing array
+	child := func(name string) []string {
+		return append(path[:len(path):len(path)], name)
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		flattenYAMLNode(node.Alias, path, joinKey, vars)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 && len(path) > 0 {
+			vars[joinKey(path)] = ""
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			flattenYAMLNode(node.Content[i+1], child(node.Content[i].Value), joinKey, vars)
+		}
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 && len(path) > 0 {
+			vars[joinKey(path)] = ""
+		}
+		for i, item := range node.Content {
+			flattenYAMLNode(item, child(strconv.Itoa(i)), joinKey, vars)
+		}
+	case yaml.ScalarNode:
+		if len(path) == 0 {
+			return
+		}
+		value := node.Value
+		if node.Tag == "!!null" {
+			value = ""
+		}
+		vars[joinKey(path)] = value
+	}
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnorePattern is a single compiled line of an ignore file
+type IgnorePattern struct {
+	Pattern string // Original pattern text
+	Negate  bool   // Pattern started with !
+	DirOnly bool   // Pattern ended with /
+	regex   *regexp.Regexp
+}
+
+// IgnoreMatcher matches paths against the patterns of a .gitignore-style file
+type IgnoreMatcher struct {
+	Patterns []IgnorePattern
+	docker   bool // .dockerignore rules, see ParseDockerignore
+}
+
+// ParseGitignore parses a .gitignore file
+func ParseGitignore(filename string) (*IgnoreMatcher, error) {
+	return parseIgnoreFile(filename, false)
+}
+
+// ParseDockerignore parses a .dockerignore file. Unlike .gitignore, every
+// pattern is relative to the build context root (`secret.env` doesn't match
+// `config/secret.env`), a pattern matching a directory covers everything in
+// it, and a later ! pattern can re-include files inside an excluded directory.
+func ParseDockerignore(filename string) (*IgnoreMatcher, error) {
+	return parseIgnoreFile(filename, true)
+}
+
+// parseIgnoreFile parses an ignore file with gitignore or dockerignore rules
+func parseIgnoreFile(filename string, docker bool) (*IgnoreMatcher, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	matcher := &IgnoreMatcher{docker: docker}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if pattern, ok := compileIgnorePattern(scanner.Text(), docker); ok {
+			matcher.Patterns = append(matcher.Patterns, pattern)
+		}
+	}
+
+	return matcher, scanner.Err()
+}
+
+// compileIgnorePattern turns one ignore file line into a pattern; docker
+// patterns are always anchored to the context root
+func compileIgnorePattern(line string, docker bool) (IgnorePattern, bool) {
+	if docker {
+		line = strings.TrimSpace(line)
+	}
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return IgnorePattern{}, false
+	}
+
+	pattern := IgnorePattern{Pattern: line}
+
+	if strings.HasPrefix(line, "!") {
+		pattern.Negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		pattern.DirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	// A slash anywhere but the end anchors the pattern to the ignore file's directory
+	anchored := docker || strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return IgnorePattern{}, false
+	}
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("^(?:.*/)?")
+	}
+	re.WriteString(globToRegex(line))
+	re.WriteString("$")
+
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return IgnorePattern{}, false
+	}
+	pattern.regex = compiled
+
+	return pattern, true
+}
+
+// globToRegex converts gitignore glob syntax (*, ?, **, [...]) to a regex
+func globToRegex(glob string) string {
+	var re strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				re.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				re.WriteString(".*")
+				i++
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			if end := strings.IndexByte(glob[i:], ']'); end > 0 {
+				class := glob[i+1 : i+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				re.WriteString("[" + class + "]")
+				i += end
+			} else {
+				re.WriteString(`\[`)
+			}
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				re.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return re.String()
+}
+
+// Matches reports whether a file path (relative to the ignore file's
+// directory) is ignored, either directly or because a parent directory is
+func (m *IgnoreMatcher) Matches(path string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+	segments := strings.Split(path, "/")
+
+	if m.docker {
+		return m.matchDocker(segments)
+	}
+
+	// Files inside an ignored directory cannot be re-included
+	for i := 1; i < len(segments); i++ {
+		if m.match(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return m.match(path, false)
+}
+
+// match applies the patterns to a single path; the last matching pattern wins
+func (m *IgnoreMatcher) match(path string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range m.Patterns {
+		if pattern.DirOnly && !isDir {
+			continue
+		}
+		if pattern.regex.MatchString(path) {
+			ignored = !pattern.Negate
+		}
+	}
+	return ignored
+}
+
+// matchDocker applies .dockerignore rules: a pattern matching the path or
+// any of its parent directories applies, and the last one that applies wins
+func (m *IgnoreMatcher) matchDocker(segments []string) bool {
+	ignored := false
+	for _, pattern := range m.Patterns {
+		for i := len(segments); i > 0; i-- {
+			if pattern.regex.MatchString(strings.Join(segments[:i], "/")) {
+				ignored = !pattern.Negate
+				break
+			}
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Inheritance boundaries for FindInheritedEnvFiles
+const (
+	InheritGitRoot = "git"  // Stop at the git repository root
+	InheritFSRoot  = "root" // Stop at the filesystem root
+)
+
+// FindInheritedEnvFiles walks up from the directory of envFile collecting
+// files with the same name, for nested projects whose .env inherits from a
+// parent's. The files are returned outermost first, ending with envFile
+// itself, so merging them in order lets the nearest file win. With
+// InheritGitRoot the walk stops at the directory holding .git, or at the
+// filesystem root outside a repository.
+func FindInheritedEnvFiles(envFile, boundary string) ([]string, error) {
+	if boundary != InheritGitRoot && boundary != InheritFSRoot {
+		return nil, fmt.Errorf("unknown inheritance boundary %q (use %s or %s)", boundary, InheritGitRoot, InheritFSRoot)
+	}
+
+	abs, err := filepath.Abs(envFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", envFile, err)
+	}
+	name := filepath.Base(abs)
+
+	files := []string{envFile}
+	for dir := filepath.Dir(abs); ; {
+		if boundary == InheritGitRoot {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				break
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			files = append([]string{candidate}, files...)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseINISections(t *testing.T) {
+	parsed, err := ParseEnvFileWithOptions("testdata/sections.ini", &ParseOptions{INI: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+		lines []int
+	}{
+		{"app_name", "envquack", []int{2}},
+		{"debug", "false", []int{3}},
+		{"DATABASE_HOST", "localhost", []int{6}},
+		{"DATABASE_PORT", "5433", []int{7, 19}},
+		{"CACHE_HOST", "redis", []int{11}},
+		{"CACHE_PORT", "6379", []int{12}},
+		{"REDIS_CACHE_TTL", "60", []int{15}},
+	}
+
+	vars := parsed.EnvVars()
+	if len(vars) != len(tests) {
+		t.Errorf("keys = %v, want %d keys", parsed.Keys(), len(tests))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := vars[tt.key]; got != tt.value {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.value)
+			}
+
+			var lines []int
+			for _, entry := range parsed.Entries {
+				if entry.Key == tt.key {
+					lines = append(lines, entry.Line)
+				}
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("%s set on lines %v, want %v", tt.key, lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestParseINISectionsOnlyWithINI(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ParseOptions
+		key  string
+		want bool
+	}{
+		{"ini prefixes keys", &ParseOptions{INI: true}, "DATABASE_HOST", true},
+		{"ini keeps default keys as written", &ParseOptions{INI: true}, "app_name", true},
+		{"dotenv does not prefix", nil, "DATABASE_HOST", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseEnvFileWithOptions("testdata/sections.ini", tt.opts)
+			if err != nil && tt.opts != nil {
+				t.Fatal(err)
+			}
+			if parsed == nil {
+				if tt.want {
+					t.Fatalf("no result: %v", err)
+				}
+				return
+			}
+			if _, ok := parsed.EnvVars()[tt.key]; ok != tt.want {
+				t.Errorf("%s present = %v, want %v", tt.key, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// k8sConfigResource is the subset of a ConfigMap or Secret manifest EnvQuack cares about
+type k8sConfigResource struct {
+	Kind       string            `yaml:"kind"`
+	Data       map[string]string `yaml:"data"`
+	StringData map[string]string `yaml:"stringData"`
+	BinaryData map[string]string `yaml:"binaryData"`
+}
+
+// ParseK8sConfigResource extracts the keys of every ConfigMap and Secret in a
+// Kubernetes YAML file, which may hold several documents separated by ---.
+// ConfigMap values are kept as-is; Secret values (base64 data and plain
+// stringData alike) are never decoded and come back empty. Other kinds are
+// ignored.
+func ParseK8sConfigResource(filename string) (EnvVars, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Kubernetes manifest: %w", err)
+	}
+
+	vars := make(EnvVars)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	for doc := 1; ; doc++ {
+		var resource k8sConfigResource
+		err := decoder.Decode(&resource)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s document %d: %w", filename, doc, err)
+		}
+
+		switch resource.Kind {
+		case "ConfigMap":
+			for key, value := range resource.Data {
+				vars[key] = value
+			}
+			for key := range resource.BinaryData {
+				vars[key] = ""
+			}
+		case "Secret":
+			for key := range resource.Data {
+				vars[key] = ""
+			}
+			for key := range resource.StringData {
+				vars[key] = ""
+			}
+		}
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MakefileEnvInfo contains environment information extracted from a Makefile
+type MakefileEnvInfo struct {
+	Defaults EnvVars  // FOO ?= value: taken from the environment when set, with a fallback
+	Required []string // Variables referenced but never assigned in the Makefile
+}
+
+var (
+	// makeAssignmentRegex matches a variable assignment with any Make operator,
+	// optionally prefixed by export or override
+	makeAssignmentRegex = regexp.MustCompile(`^(?:(?:export|override)\s+)*([A-Za-z_][A-Za-z0-9_.-]*)\s*(\?=|:::=|::=|:=|\+=|!=|=)\s*(.*)$`)
+	// makeDefineRegex matches the start of a multi-line `define NAME` block
+	makeDefineRegex = regexp.MustCompile(`^(?:(?:export|override)\s+)*define\s+([A-Za-z_][A-Za-z0-9_.-]*)`)
+	// makeRefRegex matches, in a single pass, shell references escaped for Make
+	// in recipes ($$VAR, $${VAR}) and Make references ($(VAR), ${VAR}).
+	// Automatic variables ($@, $<, $(@D)) and function calls ($(shell ...))
+	// never match.
+	makeRefRegex = regexp.MustCompile(`\$\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?|\$[({]([A-Z_][A-Z0-9_]*)[)}]`)
+)
+
+// makeInternalVars are set by Make itself, its implicit rules or the shell
+// running recipes, never by .env
+var makeInternalVars = map[string]bool{
+	"MAKE": true, "MAKEFLAGS": true, "MAKECMDGOALS": true, "MAKEFILE_LIST": true,
+	"MAKELEVEL": true, "MAKEOVERRIDES": true, "MFLAGS": true, "CURDIR": true,
+	"SHELL": true, "MAKESHELL": true, "VPATH": true, "SUFFIXES": true,
+	"CC": true, "CXX": true, "CPP": true, "AR": true, "AS": true, "LD": true,
+	"RM": true, "CFLAGS": true, "CXXFLAGS": true, "CPPFLAGS": true,
+	"LDFLAGS": true, "LDLIBS": true, "ARFLAGS": true,
+	"HOME": true, "PATH": true, "PWD": true, "USER": true,
+}
+
+// ParseMakefile extracts the environment variables a Makefile relies on.
+// Variables assigned in the Makefile (=, :=, +=, define, ...) are Make
+// variables and not required; ?= assignments are defaults the environment
+// can override. Everything else referenced as $(VAR) or ${VAR}, or as $$VAR
+// in recipes, must come from the environment. Lines continued with \ are
+// joined and comments are skipped.
+func ParseMakefile(filename string) (*MakefileEnvInfo, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Makefile: %w", err)
+	}
+	defer file.Close()
+
+	info := &MakefileEnvInfo{
+		Defaults: make(EnvVars),
+		Required: []string{},
+	}
+	defined := make(map[string]bool)
+	refSet := make(map[string]bool)
+	inDefine := false
+
+	scanner := bufio.NewScanner(file)
+	var logical strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Join continuation lines into one logical line
+		if strings.HasSuffix(line, `\`) {
+			logical.WriteString(strings.TrimSuffix(line, `\`) + " ")
+			continue
+		}
+		logical.WriteString(line)
+		line = logical.String()
+		logical.Reset()
+
+		// Recipe lines run in the shell; everything else is Make syntax
+		isRecipe := strings.HasPrefix(line, "\t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !isRecipe {
+			if inDefine {
+				inDefine = trimmed != "endef"
+				if inDefine {
+					collectMakeRefs(trimmed, refSet)
+				}
+				continue
+			}
+			if m := makeDefineRegex.FindStringSubmatch(trimmed); m != nil {
+				defined[m[1]] = true
+				inDefine = true
+				continue
+			}
+
+			trimmed = stripMakeComment(trimmed)
+			if m := makeAssignmentRegex.FindStringSubmatch(trimmed); m != nil {
+				if m[2] == "?=" {
+					if !defined[m[1]] {
+						info.Defaults[m[1]] = m[3]
+					}
+				} else {
+					defined[m[1]] = true
+					delete(info.Defaults, m[1])
+				}
+				collectMakeRefs(m[3], refSet)
+				continue
+			}
+		}
+
+		// Rules (.PHONY included), directives and recipes
+		collectMakeRefs(trimmed, refSet)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Makefile: %w", err)
+	}
+
+	for ref := range refSet {
+		if !defined[ref] && !info.Defaults.Has(ref) && !makeInternalVars[ref] {
+			info.Required = append(info.Required, ref)
+		}
+	}
+	sort.Strings(info.Required)
+
+	return info, nil
+}
+
+// collectMakeRefs adds the variables referenced in text to refSet
+func collectMakeRefs(text string, refSet map[string]bool) {
+	for _, match := range makeRefRegex.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		// Shell variables in recipes follow the env naming convention;
+		// lowercase ones are loop and local variables
+		if name == strings.ToUpper(name) {
+			refSet[name] = true
+		}
+	}
+}
+
+// stripMakeComment removes a trailing # comment from a Make line (\# is a literal #)
+func stripMakeComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
@@ -0,0 +1,13 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/DuckDHD/EnvQuack/internal/testutil"
+)
+
+// TestNoProcessSideEffects guards the package's embeddability: no code path
+// may exit the process or print, only cmd/envquack and internal/cli do.
+func TestNoProcessSideEffects(t *testing.T) {
+	testutil.AssertNoProcessSideEffects(t, ".")
+}
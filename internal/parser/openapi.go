@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serverTemplateRegex matches {name} placeholders in OpenAPI server URLs
+var serverTemplateRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// openAPIServer is a server object of an OpenAPI 3 document
+type openAPIServer struct {
+	URL       string `yaml:"url"`
+	Variables map[string]struct {
+		Default *string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+// openAPIDocument is the subset of an OpenAPI 3 document EnvQuack cares about
+type openAPIDocument struct {
+	Servers []openAPIServer                 `yaml:"servers"`
+	Paths   map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+// OpenAPIServerInfo holds the server variables of an OpenAPI document
+type OpenAPIServerInfo struct {
+	Required []string // Variables without a default, sorted
+	Defaults EnvVars  // Variables with a default value
+}
+
+// ParseOpenAPIServers extracts server variables from an OpenAPI 3 document in
+// YAML or JSON, looking at the top-level servers and those of paths and
+// operations. A {placeholder} in a server URL without a variables entry is
+// treated as a variable without a default. Swagger 2 documents have no
+// server variables and yield none.
+func ParseOpenAPIServers(filename string) (*OpenAPIServerInfo, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder covers both formats
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	servers := append([]openAPIServer{}, doc.Servers...)
+	for _, item := range doc.Paths {
+		for field, node := range item {
+			if node.Kind != yaml.MappingNode && field != "servers" {
+				continue
+			}
+
+			if field == "servers" {
+				var pathServers []openAPIServer
+				if err := node.Decode(&pathServers); err != nil {
+					return nil, fmt.Errorf("failed to parse path servers in %s: %w", filename, err)
+				}
+				servers = append(servers, pathServers...)
+				continue
+			}
+
+			var operation struct {
+				Servers []openAPIServer `yaml:"servers"`
+			}
+			if err := node.Decode(&operation); err != nil {
+				return nil, fmt.Errorf("failed to parse operation servers in %s: %w", filename, err)
+			}
+			servers = append(servers, operation.Servers...)
+		}
+	}
+
+	info := &OpenAPIServerInfo{
+		Required: []string{},
+		Defaults: make(EnvVars),
+	}
+
+	required := make(map[string]bool)
+	for _, server := range servers {
+		for name, variable := range server.Variables {
+			if variable.Default != nil {
+				info.Defaults[name] = *variable.Default
+			} else {
+				required[name] = true
+			}
+		}
+
+		for _, match := range serverTemplateRegex.FindAllStringSubmatch(server.URL, -1) {
+			if _, declared := server.Variables[match[1]]; !declared {
+				required[match[1]] = true
+			}
+		}
+	}
+
+	// A default anywhere makes the variable optional
+	for name := range required {
+		if !info.Defaults.Has(name) {
+			info.Required = append(info.Required, name)
+		}
+	}
+	sort.Strings(info.Required)
+
+	return info, nil
+}
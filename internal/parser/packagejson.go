@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PackageScriptsEnvInfo contains environment information extracted from package.json scripts
+type PackageScriptsEnvInfo struct {
+	Defined  EnvVars  // Inline KEY=value assignments, e.g. "PORT=3000 node ."
+	Required []string // Variables referenced as $VAR, ${VAR} or %VAR% but never assigned inline
+}
+
+// packageJSON is the subset of package.json EnvQuack cares about
+type packageJSON struct {
+	Scripts map[string]string `json:"scripts"`
+}
+
+var (
+	// scriptAssignmentRegex matches a leading KEY=value shell assignment token
+	scriptAssignmentRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+	// scriptRefRegex matches $VAR, ${VAR} and Windows style %VAR% references
+	scriptRefRegex = regexp.MustCompile(`\$\{?([A-Z_][A-Z0-9_]*)\}?|%([A-Z_][A-Z0-9_]*)%`)
+	// scriptSeparatorRegex splits a script into individual commands
+	scriptSeparatorRegex = regexp.MustCompile(`&&|\|\||[;|]`)
+)
+
+// scriptEnvPrefixes are launcher commands that take KEY=value arguments before the real command
+var scriptEnvPrefixes = map[string]bool{
+	"cross-env":       true,
+	"cross-env-shell": true,
+	"env":             true,
+	"npx":             true,
+}
+
+// ParsePackageJSON extracts inline env assignments and variable references from package.json scripts
+func ParsePackageJSON(filename string) (*PackageScriptsEnvInfo, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	info := &PackageScriptsEnvInfo{
+		Defined:  make(EnvVars),
+		Required: []string{},
+	}
+
+	refSet := make(map[string]bool)
+	for _, script := range pkg.Scripts {
+		for _, command := range scriptSeparatorRegex.Split(script, -1) {
+			parseScriptAssignments(command, info.Defined)
+		}
+
+		for _, match := range scriptRefRegex.FindAllStringSubmatch(script, -1) {
+			if match[1] != "" {
+				refSet[match[1]] = true
+			} else if match[2] != "" {
+				refSet[match[2]] = true
+			}
+		}
+	}
+
+	for ref := range refSet {
+		if !info.Defined.Has(ref) {
+			info.Required = append(info.Required, ref)
+		}
+	}
+	sort.Strings(info.Required)
+
+	return info, nil
+}
+
+// parseScriptAssignments records the KEY=value tokens leading a single command,
+// skipping launchers such as cross-env
+func parseScriptAssignments(command string, defined EnvVars) {
+	for _, token := range strings.Fields(command) {
+		if scriptEnvPrefixes[token] {
+			continue
+		}
+
+		match := scriptAssignmentRegex.FindStringSubmatch(token)
+		if match == nil {
+			// The first non-assignment token is the command itself
+			return
+		}
+
+		defined[match[1]] = strings.Trim(match[2], `"'`)
+	}
+}
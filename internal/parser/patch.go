@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffLine is one line of a file, remembering whether it ended in a newline
+type diffLine struct {
+	text       string
+	terminated bool
+}
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// splitDiffLines splits content into lines for UnifiedDiff
+func splitDiffLines(content string) []diffLine {
+	lines := []diffLine{}
+	for content != "" {
+		end := strings.IndexByte(content, '\n')
+		if end < 0 {
+			lines = append(lines, diffLine{text: content})
+			break
+		}
+		lines = append(lines, diffLine{text: content[:end], terminated: true})
+		content = content[end+1:]
+	}
+	return lines
+}
+
+// UnifiedDiff renders the change from old to new content of filename as a
+// unified diff that git apply accepts, or "" when nothing changed. Everything
+// after the lines both versions start with becomes a single hunk, which is
+// minimal for the appends EnvQuack makes. An empty old is a new file.
+func UnifiedDiff(filename, old, new string) string {
+	if old == new {
+		return ""
+	}
+
+	oldLines := splitDiffLines(old)
+	newLines := splitDiffLines(new)
+
+	common := 0
+	for common < len(oldLines) && common < len(newLines) && oldLines[common] == newLines[common] {
+		common++
+	}
+	start := max(common-diffContext, 0)
+
+	var diff strings.Builder
+	if old == "" {
+		diff.WriteString("--- /dev/null\n")
+	} else {
+		diff.WriteString("--- a/" + filename + "\n")
+	}
+	diff.WriteString("+++ b/" + filename + "\n")
+
+	oldCount := len(oldLines) - start
+	newCount := len(newLines) - start
+	diff.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(start, oldCount), hunkRange(start, newCount)))
+
+	for _, line := range oldLines[start:common] {
+		diff.WriteString(" " + line.text + "\n")
+	}
+	writeDiffLines(&diff, "-", oldLines[common:])
+	writeDiffLines(&diff, "+", newLines[common:])
+
+	return diff.String()
+}
+
+// hunkRange renders the start,count of a hunk header; an empty range starts
+// at the line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// writeDiffLines writes lines with a +/- prefix, marking a missing final newline
+func writeDiffLines(diff *strings.Builder, prefix string, lines []diffLine) {
+	for _, line := range lines {
+		diff.WriteString(prefix + line.text + "\n")
+		if !line.terminated {
+			diff.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// CompileRefPattern compiles a user supplied reference pattern, which must
+// contain exactly one capture group for the variable name
+func CompileRefPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference pattern: %w", err)
+	}
+
+	if re.NumSubexp() != 1 {
+		return nil, fmt.Errorf("reference pattern must have exactly one capture group, found %d", re.NumSubexp())
+	}
+
+	return re, nil
+}
+
+// ExtractRefsFromFiles returns the sorted, unique variable names captured by re in the given files
+func ExtractRefsFromFiles(re *regexp.Regexp, files []string) ([]string, error) {
+	varSet := make(map[string]bool)
+
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+
+		for _, match := range re.FindAllStringSubmatch(string(content), -1) {
+			if len(match) > 1 && match[1] != "" {
+				varSet[match[1]] = true
+			}
+		}
+	}
+
+	vars := make([]string, 0, len(varSet))
+	for varName := range varSet {
+		vars = append(vars, varName)
+	}
+	sort.Strings(vars)
+
+	return vars, nil
+}
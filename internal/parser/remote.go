@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RemoteLimits bounds how http(s) sources are fetched
+type RemoteLimits struct {
+	Timeout      time.Duration // Whole request, including reading the body
+	MaxBytes     int64         // Largest accepted response body
+	MaxRedirects int           // Redirects followed before giving up
+}
+
+// DefaultRemoteLimits returns limits suitable for untrusted endpoints
+func DefaultRemoteLimits() *RemoteLimits {
+	return &RemoteLimits{
+		Timeout:      10 * time.Second,
+		MaxBytes:     10 << 20,
+		MaxRedirects: 5,
+	}
+}
+
+// remoteLimits applies to every remote fetch, see SetRemoteLimits
+var remoteLimits = DefaultRemoteLimits()
+
+// SetRemoteLimits replaces the limits for remote sources; nil restores the defaults
+func SetRemoteLimits(limits *RemoteLimits) {
+	if limits == nil {
+		limits = DefaultRemoteLimits()
+	}
+	remoteLimits = limits
+}
+
+// IsRemoteSource reports whether a file argument is an http(s) URL
+func IsRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// ReadRemoteSource downloads an http(s) source within the configured limits.
+// Exceeding a limit is an error naming the limit, never a partial read.
+func ReadRemoteSource(url string) ([]byte, error) {
+	limits := remoteLimits
+
+	client := &http.Client{
+		Timeout: limits.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > limits.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", limits.MaxRedirects)
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, remoteError(url, err, limits)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	if resp.ContentLength > limits.MaxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: response of %d bytes exceeds the %d byte limit", url, resp.ContentLength, limits.MaxBytes)
+	}
+
+	// Read one byte past the limit to tell a full body from a truncated one
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limits.MaxBytes+1))
+	if err != nil {
+		return nil, remoteError(url, err, limits)
+	}
+	if int64(len(data)) > limits.MaxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: response exceeds the %d byte limit", url, limits.MaxBytes)
+	}
+
+	return data, nil
+}
+
+// remoteError describes a failed fetch, calling out timeouts explicitly
+func remoteError(url string, err error, limits *RemoteLimits) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("failed to fetch %s: timed out after %s", url, limits.Timeout)
+	}
+	return fmt.Errorf("failed to fetch %s: %w", url, err)
+}
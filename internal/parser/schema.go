@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SchemaTypes are the value types a schema field can declare
+var SchemaTypes = []string{"string", "int", "bool", "url"}
+
+// SchemaField is one declared variable of a .env.schema file
+type SchemaField struct {
+	Key      string
+	Type     string // One of SchemaTypes
+	Required bool
+	Line     int // 1-based line of the declaration
+}
+
+// ParseSchemaFile reads a schema of KEY:type:required lines, e.g.
+// `PORT:int:required` or `DEBUG:bool:optional`. The last part may be left
+// out and defaults to required. Blank lines and # comments are skipped.
+func ParseSchemaFile(filename string) ([]SchemaField, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema file: %w", err)
+	}
+	defer file.Close()
+
+	fields := []SchemaField{}
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ":")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY:type:required, got %q", filename, lineNum, line)
+		}
+
+		field := SchemaField{Key: parts[0], Type: strings.ToLower(parts[1]), Required: true, Line: lineNum}
+		if !isSchemaType(field.Type) {
+			return nil, fmt.Errorf("%s:%d: unknown type %q for %s (use %s)", filename, lineNum, parts[1], field.Key, strings.Join(SchemaTypes, ", "))
+		}
+		if len(parts) == 3 {
+			switch strings.ToLower(parts[2]) {
+			case "required":
+			case "optional":
+				field.Required = false
+			default:
+				return nil, fmt.Errorf("%s:%d: expected required or optional for %s, got %q", filename, lineNum, field.Key, parts[2])
+			}
+		}
+
+		if first, dup := seen[field.Key]; dup {
+			return nil, fmt.Errorf("%s:%d: %s is already declared on line %d", filename, lineNum, field.Key, first)
+		}
+		seen[field.Key] = lineNum
+
+		fields = append(fields, field)
+	}
+
+	return fields, scanner.Err()
+}
+
+// isSchemaType reports whether typ is one of SchemaTypes
+func isSchemaType(typ string) bool {
+	for _, t := range SchemaTypes {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
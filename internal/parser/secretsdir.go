@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseSecretsDir reads a directory of per-variable files, as Docker and
+// Kubernetes mount secrets (/run/secrets/DB_PASSWORD): each file name is a
+// key and its contents, minus one trailing newline, the value. Nested
+// directories are walked too. Dotfiles and dot directories are skipped,
+// which also skips the ..data links of Kubernetes volume mounts. A file name
+// found twice is an error, since it is unclear which file wins.
+func ParseSecretsDir(dir string) (EnvVars, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	vars := make(EnvVars)
+	seen := make(map[string]string)
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		// Mounted secrets are usually symlinks; follow them to the file
+		target, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !target.Mode().IsRegular() {
+			return nil
+		}
+
+		key := d.Name()
+		if first, dup := seen[key]; dup {
+			return fmt.Errorf("%s is defined twice: %s and %s", key, first, path)
+		}
+		seen[key] = path
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		value := strings.TrimSuffix(string(data), "\n")
+		vars[key] = strings.TrimSuffix(value, "\r")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets directory: %w", err)
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,98 @@
+package parser
+
+import "strings"
+
+// FormatAssignment renders a single KEY=value line (without newline), double
+// quoting values that would not survive an unquoted round trip
+func FormatAssignment(key, value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\n#\"'`$\\") {
+		return key + "=" + value
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return key + `="` + escaped + `"`
+}
+
+// FormatEnvBlock renders vars as KEY=value lines in the given key order
+func FormatEnvBlock(keys []string, vars EnvVars) string {
+	var block strings.Builder
+	for _, key := range keys {
+		block.WriteString(FormatAssignment(key, vars[key]) + "\n")
+	}
+	return block.String()
+}
+
+// FormatKeyList renders bare key names, one per line
+func FormatKeyList(keys []string) string {
+	var list strings.Builder
+	for _, key := range keys {
+		list.WriteString(key + "\n")
+	}
+	return list.String()
+}
+
+// FormatDocumentedEntry renders doc as # comment lines directly above a
+// KEY=value line, the layout ParseEnvFileOrdered reads back as the entry's Doc
+func FormatDocumentedEntry(key, value string, doc []string) string {
+	var entry strings.Builder
+	for _, line := range doc {
+		entry.WriteString("# " + line + "\n")
+	}
+	entry.WriteString(FormatAssignment(key, value) + "\n")
+	return entry.String()
+}
+
+// RemoveEntries returns env file content without the assignments of keys,
+// each together with the comment block directly above it. A blank line the
+// removal would leave doubled is dropped as well.
+func RemoveEntries(content string, keys []string) (string, error) {
+	parsed, err := ParseEnvReader(strings.NewReader(content), "", nil)
+	if err != nil {
+		return "", err
+	}
+
+	remove := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		remove[key] = true
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	drop := make(map[int]bool)
+	for _, entry := range parsed.Entries {
+		if !remove[entry.Key] {
+			continue
+		}
+		i := entry.Line - 1
+		for l := i; l < entry.EndLine; l++ {
+			drop[l] = true
+		}
+		for j := i - 1; j >= 0 && strings.HasPrefix(strings.TrimSpace(lines[j]), "#"); j-- {
+			drop[j] = true
+		}
+	}
+
+	var out strings.Builder
+	lastBlank, dropped := true, false
+	for i, line := range lines {
+		if drop[i] {
+			dropped = true
+			continue
+		}
+		blank := strings.TrimSpace(line) == ""
+		if blank && line != "" && lastBlank && dropped {
+			continue
+		}
+		out.WriteString(line)
+		lastBlank, dropped = blank, false
+	}
+
+	result := out.String()
+	if dropped {
+		// The file ended with removed entries; keep no trailing blank line
+		result = strings.TrimRight(result, "\n")
+		if result != "" {
+			result += "\n"
+		}
+	}
+	return result, nil
+}
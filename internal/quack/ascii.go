@@ -2,6 +2,9 @@ package quack
 
 // GetHappyDuck returns ASCII art for when everything is fine
 func GetHappyDuck() string {
+	if duck := customDuck(MoodHappy); duck != "" {
+		return duck
+	}
 	return `   __
 <(o )___   All good!
  ( ._> /
@@ -10,12 +13,84 @@ func GetHappyDuck() string {
 
 // GetAngryDuck returns ASCII art for when there are issues
 func GetAngryDuck() string {
+	if duck := customDuck(MoodAngry); duck != "" {
+		return duck
+	}
 	return `   __
 <(X )___   QUACK!
  ( ._> /
   '---'`
 }
 
+// GetContentDuck returns ASCII art for when there are only warnings
+func GetContentDuck() string {
+	if duck := customDuck(MoodContent); duck != "" {
+		return duck
+	}
+	return `   __
+<(- )___   Quack.
+ ( ._> /
+  '---'`
+}
+
+// GetConcernedDuck returns ASCII art for a couple of issues
+func GetConcernedDuck() string {
+	if duck := customDuck(MoodConcerned); duck != "" {
+		return duck
+	}
+	return `   __
+<(. )___   Quack?
+ ( ._> /
+  '---'`
+}
+
+// GetOverwhelmedDuck returns ASCII art for when issues pile up
+func GetOverwhelmedDuck() string {
+	if duck := customDuck(MoodOverwhelmed); duck != "" {
+		return duck
+	}
+	return `   __  ~~
+<(@ )___   QUAAACK!!
+ ( ._> /
+  '---'`
+}
+
+// Duck moods, from calmest to most upset
+const (
+	MoodContent     = "content"
+	MoodConcerned   = "concerned"
+	MoodAngry       = "angry"
+	MoodOverwhelmed = "overwhelmed"
+)
+
+// MoodForSeverity names the duck's mood by how many variables are missing or
+// extra: content for none, concerned for 1-2, angry for 3-9 and overwhelmed
+// from 10
+func MoodForSeverity(missing, extra int) string {
+	switch total := missing + extra; {
+	case total == 0:
+		return MoodContent
+	case total <= 2:
+		return MoodConcerned
+	case total < 10:
+		return MoodAngry
+	}
+	return MoodOverwhelmed
+}
+
+// GetDuckForSeverity picks the duck for the mood of MoodForSeverity
+func GetDuckForSeverity(missing, extra int) string {
+	switch MoodForSeverity(missing, extra) {
+	case MoodContent:
+		return GetContentDuck()
+	case MoodConcerned:
+		return GetConcernedDuck()
+	case MoodAngry:
+		return GetAngryDuck()
+	}
+	return GetOverwhelmedDuck()
+}
+
 // GetBanner returns the main EnvQuack banner
 func GetBanner() string {
 	return `
@@ -30,8 +105,22 @@ Environment Variable Drift Detective 🦆
 
 // GetSyncMessage returns a message for sync operations
 func GetSyncMessage() string {
+	if duck := customDuck(MoodSyncing); duck != "" {
+		return duck
+	}
 	return `   __
 <(~ )___   Syncing...
  ( ._> /
   '---'`
 }
+
+// GetInitMessage returns a message for a freshly scaffolded example file
+func GetInitMessage() string {
+	if duck := customDuck(MoodHappy); duck != "" {
+		return duck
+	}
+	return `   __
+<(^ )___   Hatched a new .env.example!
+ ( ._> /
+  '---'`
+}
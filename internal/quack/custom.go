@@ -0,0 +1,24 @@
+package quack
+
+// Custom art replacing the built-in ducks, empty for the built-in ones
+var (
+	customHappy string
+	customAngry string
+)
+
+// SetCustomDuck replaces the built-in art of every duck: happy is shown
+// when all is well or there are only warnings, angry when there are issues.
+// An empty string keeps the built-in art for that side.
+func SetCustomDuck(happy, angry string) {
+	customHappy = happy
+	customAngry = angry
+}
+
+// customDuck returns the custom art for mood, empty when there is none
+func customDuck(mood string) string {
+	switch mood {
+	case MoodConcerned, MoodAngry, MoodOverwhelmed:
+		return customAngry
+	}
+	return customHappy
+}
@@ -0,0 +1,104 @@
+package quack
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Moods beyond the severity scale, for GetRandomDuck
+const (
+	MoodHappy   = "happy"
+	MoodSyncing = "syncing"
+)
+
+// duckVariants holds the art of each mood; the first variant is the one the
+// Get*Duck functions always return
+var duckVariants = map[string][]string{
+	MoodHappy: {
+		GetHappyDuck(),
+		`   __
+<(o )___   Nothing to quack about!
+ ( ._> /
+  '---'`,
+		`   __
+<(^ )___   Smooth sailing!
+ ( ._> /
+  '---'`,
+		`   __
+<(o )___   All ducks in a row!
+ ( ._> /  ~~
+  '---'`,
+	},
+	MoodContent: {
+		GetContentDuck(),
+		`   __
+<(- )___   Mostly fine.
+ ( ._> /
+  '---'`,
+		`   __
+<(- )___   Quack, I guess.
+ ( ._> /
+  '---'`,
+	},
+	MoodConcerned: {
+		GetConcernedDuck(),
+		`   __
+<(. )___   Hmm, quack?
+ ( ._> /
+  '---'`,
+		`   __
+<(o )___   Something's off...
+ ( ._> /
+  '---'`,
+	},
+	MoodAngry: {
+		GetAngryDuck(),
+		`   __
+<(X )___   QUACK QUACK!
+ ( ._> /
+  '---'`,
+		`   __
+<(# )___   Who touched my .env?!
+ ( ._> /
+  '---'`,
+	},
+	MoodOverwhelmed: {
+		GetOverwhelmedDuck(),
+		`   __  ~~
+<(@ )___   Too many quacks!!
+ ( ._> /
+  '---'`,
+		`   __ ~~~
+<(@ )___   I need a bigger pond!
+ ( ._> /
+  '---'`,
+	},
+	MoodSyncing: {
+		GetSyncMessage(),
+		`   __
+<(~ )___   Paddling values over...
+ ( ._> /  ~~
+  '---'`,
+		`   __
+<(~ )___   Filling the pond...
+ ( ._> /
+  '---'`,
+	},
+}
+
+// duckRand picks the variants, seeded from the clock
+var duckRand = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+
+// GetRandomDuck returns a random variant of the duck for mood, one of the
+// Mood constants, or the custom duck when one is set; unknown moods get the
+// happy duck
+func GetRandomDuck(mood string) string {
+	if duck := customDuck(mood); duck != "" {
+		return duck
+	}
+	variants, ok := duckVariants[mood]
+	if !ok {
+		return GetHappyDuck()
+	}
+	return variants[duckRand.IntN(len(variants))]
+}
@@ -0,0 +1,138 @@
+// Package testutil holds helpers shared by the tests of several packages.
+package testutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// forbidden lists, by import path, what would end or write to the process
+// an embedding program owns. Any reference counts, so passing os.Stdout to
+// fmt.Fprint or keeping os.Exit in a variable is caught too.
+var forbidden = map[string]map[string]bool{
+	"os":  {"Exit": true, "Stdout": true, "Stderr": true},
+	"log": {"Fatal": true, "Fatalf": true, "Fatalln": true, "Panic": true, "Panicf": true, "Panicln": true},
+	"fmt": {"Print": true, "Printf": true, "Println": true},
+}
+
+// AssertNoProcessSideEffects fails t for every use in the non-test Go files
+// of dir of something that exits the process, panics through log or writes
+// to stdout or stderr. Only cmd/envquack and internal/cli may.
+func AssertNoProcessSideEffects(t *testing.T, dir string) {
+	t.Helper()
+
+	found, err := ProcessSideEffects(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range found {
+		t.Error(f)
+	}
+}
+
+// ProcessSideEffects returns "file:line:col: uses pkg.Name" for every use of
+// a forbidden function or stream in the non-test Go files of dir, following
+// renamed and dot imports
+func ProcessSideEffects(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	found := []string{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := parser.ParseFile(fset, file, src, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// Local name of each watched import; dot imports are ""
+		imports := make(map[string]string)
+		for _, spec := range tree.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			if forbidden[importPath] == nil {
+				continue
+			}
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if name == "." {
+				name = ""
+			}
+			imports[importPath] = name
+		}
+
+		report := func(n ast.Node, name string) {
+			found = append(found, fmt.Sprintf("%s: uses %s", fset.Position(n.Pos()), name))
+		}
+		ast.Inspect(tree, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if name, ok := lookup(imports, n); ok {
+					report(n, name)
+				}
+				// Past a plain pkg.Name the member is not a dot import use
+				if x, ok := n.X.(*ast.Ident); ok {
+					if name, ok := lookup(imports, x); ok {
+						report(x, name)
+					}
+					return false
+				}
+			case *ast.Ident:
+				if name, ok := lookup(imports, n); ok {
+					report(n, name)
+				}
+			}
+			return true
+		})
+	}
+
+	return found, nil
+}
+
+// lookup reports whether expr names a forbidden member of a watched import,
+// as pkg.Name, or as Name through a dot import
+func lookup(imports map[string]string, expr ast.Expr) (string, bool) {
+	var local, member string
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+		local, member = pkg.Name, e.Sel.Name
+	case *ast.Ident:
+		member = e.Name
+	default:
+		return "", false
+	}
+
+	for importPath, name := range imports {
+		if name == local && forbidden[importPath][member] {
+			return importPath + "." + member, true
+		}
+	}
+	return "", false
+}
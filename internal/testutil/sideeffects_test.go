@@ -0,0 +1,89 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcessSideEffects(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "clean",
+			src:  "import \"fmt\"\n\nfunc F() string { return fmt.Sprint(1) }\n",
+		},
+		{
+			name: "exit",
+			src:  "import \"os\"\n\nfunc F() { os.Exit(1) }\n",
+			want: []string{"a.go:5:12: uses os.Exit"},
+		},
+		{
+			name: "log fatal and panic",
+			src:  "import \"log\"\n\nfunc F() {\n\tlog.Fatalf(\"x\")\n\tlog.Panicln(\"x\")\n}\n",
+			want: []string{"a.go:6:2: uses log.Fatalf", "a.go:7:2: uses log.Panicln"},
+		},
+		{
+			name: "print",
+			src:  "import \"fmt\"\n\nfunc F() { fmt.Println(1) }\n",
+			want: []string{"a.go:5:12: uses fmt.Println"},
+		},
+		{
+			name: "fprint to stdout",
+			src:  "import (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc F() { fmt.Fprint(os.Stdout, 1) }\n",
+			want: []string{"a.go:8:23: uses os.Stdout"},
+		},
+		{
+			name: "stderr write",
+			src:  "import \"os\"\n\nfunc F() { os.Stderr.WriteString(\"x\") }\n",
+			want: []string{"a.go:5:12: uses os.Stderr"},
+		},
+		{
+			name: "renamed import",
+			src:  "import sys \"os\"\n\nfunc F() { sys.Exit(1) }\n",
+			want: []string{"a.go:5:12: uses os.Exit"},
+		},
+		{
+			name: "dot import",
+			src:  "import . \"os\"\n\nfunc F() { Exit(1) }\n",
+			want: []string{"a.go:5:12: uses os.Exit"},
+		},
+		{
+			name: "function value",
+			src:  "import \"os\"\n\nvar exit = os.Exit\n",
+			want: []string{"a.go:5:12: uses os.Exit"},
+		},
+		{
+			name: "same name, other package",
+			src:  "import os \"example.com/os\"\n\nfunc F() { os.Exit(1) }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\n"+tt.src), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("package a\n\nimport \"os\"\n\nfunc G() { os.Exit(1) }\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			found, err := ProcessSideEffects(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{}
+			for _, w := range tt.want {
+				want = append(want, filepath.Join(dir, w))
+			}
+			if !reflect.DeepEqual(found, want) {
+				t.Errorf("ProcessSideEffects() = %q, want %q", found, want)
+			}
+		})
+	}
+}